@@ -0,0 +1,264 @@
+package podcast
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// atomFeed is the Atom 1.0 <feed> document written by Podcast.EncodeAtom.
+//
+// https://tools.ietf.org/html/rfc4287
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"feed"`
+	XMLNS     string      `xml:"xmlns,attr"`
+	ITUNESNS  string      `xml:"xmlns:itunes,attr"`
+	ID        string      `xml:"id"`
+	Title     atomText    `xml:"title"`
+	Subtitle  *atomText   `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []*atomLink `xml:"link"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Rights    string      `xml:"rights,omitempty"`
+	Generator string      `xml:"generator,omitempty"`
+	Logo      string      `xml:"logo,omitempty"`
+	Category  []*atomTerm `xml:"category"`
+
+	IAuthor     string `xml:"itunes:author,omitempty"`
+	ISubtitle   string `xml:"itunes:subtitle,omitempty"`
+	IType       string `xml:"itunes:type,omitempty"`
+	IBlock      string `xml:"itunes:block,omitempty"`
+	IImage      *IImage
+	IExplicit   string `xml:"itunes:explicit,omitempty"`
+	IComplete   string `xml:"itunes:complete,omitempty"`
+	INewFeedURL string `xml:"itunes:new-feed-url,omitempty"`
+	IOwner      *Author
+	ICategories []*ICategory
+
+	Entries []*atomEntry `xml:"entry"`
+}
+
+// atomEntry is a single Atom <entry>.
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     atomText    `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Links     []*atomLink `xml:"link"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Category  []*atomTerm `xml:"category"`
+	Summary   *atomText   `xml:"summary,omitempty"`
+	Content   *atomText   `xml:"content,omitempty"`
+
+	IAuthor            string `xml:"itunes:author,omitempty"`
+	ITitle             string `xml:"itunes:title,omitempty"`
+	SeasonNumber       string `xml:"itunes:season,omitempty"`
+	EpisodeNumber      string `xml:"itunes:episode,omitempty"`
+	EpisodeType        string `xml:"itunes:episodeType,omitempty"`
+	ISubtitle          string `xml:"itunes:subtitle,omitempty"`
+	IImage             *IImage
+	IBlock             string `xml:"itunes:block,omitempty"`
+	IDuration          string `xml:"itunes:duration,omitempty"`
+	IExplicit          string `xml:"itunes:explicit,omitempty"`
+	IIsClosedCaptioned string `xml:"itunes:isClosedCaptioned,omitempty"`
+	IOrder             string `xml:"itunes:order,omitempty"`
+}
+
+// atomText is an Atom text construct.
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+// atomLink is an Atom <link>.  Unlike AtomLink it is written in the
+// default namespace of the Atom document.
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type atomTerm struct {
+	Term string `xml:"term,attr"`
+}
+
+// EncodeAtom writes the bytes to the io.Writer stream in Atom 1.0
+// specification.
+//
+// Each Item becomes an <entry> whose id is the GUID, whose published and
+// updated dates are the PubDate, and whose Enclosure is written as
+// <link rel="enclosure">.  The iTunes tags are kept as extension elements
+// on both the <feed> and each <entry>.
+//
+// atomURL is where the Atom document itself is published; it is the id
+// and the rel="self" link of the feed and is required.  The AtomLink of
+// the RSS feed is written as a rel="alternate" link.  An Item without a
+// GUID or Link is identified by its Enclosure URL, and one with none of
+// them is an error, as Atom requires an id for every entry.
+//
+// The feed updated date is the LastBuildDate, or else the newest Item or
+// channel PubDate; Atom requires one so the current time is used as a
+// last resort.
+func (p *Podcast) EncodeAtom(w io.Writer, atomURL string) error {
+	f, err := p.encodable().atomFeed(atomURL)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(HEADER)); err != nil {
+		return errors.Wrap(err, "podcast.EncodeAtom: w.Write return error")
+	}
+	return p.encode(w, f)
+}
+
+func (p *Podcast) atomFeed(atomURL string) (*atomFeed, error) {
+	if len(atomURL) == 0 {
+		return nil, errors.New("podcast.EncodeAtom: atomURL is required")
+	}
+	updated := p.atomUpdated()
+	f := &atomFeed{
+		XMLNS:     ATOMNS,
+		ITUNESNS:  ITUNESNS,
+		ID:        atomURL,
+		Title:     atomText{Text: p.Title},
+		Updated:   updated,
+		Rights:    p.Copyright,
+		Generator: p.Generator,
+
+		IAuthor:     p.IAuthor,
+		ISubtitle:   p.ISubtitle,
+		IType:       p.IType,
+		IBlock:      p.IBlock,
+		IImage:      p.IImage,
+		IExplicit:   p.IExplicit,
+		IComplete:   p.IComplete,
+		INewFeedURL: p.INewFeedURL,
+		IOwner:      p.IOwner,
+		ICategories: p.ICategories,
+	}
+	f.Links = append(f.Links, &atomLink{Href: atomURL, Rel: "self", Type: "application/atom+xml"})
+	if len(p.Link) != 0 {
+		f.Links = append(f.Links, &atomLink{Href: p.Link, Rel: "alternate", Type: "text/html"})
+	}
+	if p.AtomLink != nil {
+		f.Links = append(f.Links, &atomLink{Href: p.AtomLink.HREF, Rel: "alternate", Type: "application/rss+xml"})
+	}
+	for _, l := range p.PagingLinks {
+		f.Links = append(f.Links, &atomLink{Href: l.HREF, Rel: l.Rel, Type: l.Type})
+	}
+	if p.Description != nil && len(p.Description.Text) != 0 {
+		f.Subtitle = &atomText{Type: "html", Text: p.Description.Text}
+	}
+	if len(p.IAuthor) != 0 {
+		f.Author = &atomPerson{Name: p.IAuthor}
+	}
+	if p.Image != nil {
+		f.Logo = p.Image.URL
+	}
+	for _, c := range p.ICategories {
+		f.Category = append(f.Category, &atomTerm{Term: c.Text})
+		for _, sub := range c.ICategories {
+			f.Category = append(f.Category, &atomTerm{Term: sub.Text})
+		}
+	}
+	for n, i := range p.Items {
+		e := i.atomEntry(updated)
+		if len(e.ID) == 0 {
+			return nil, errors.Errorf("podcast.EncodeAtom: Items[%d] has no GUID, Link or Enclosure", n)
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	return f, nil
+}
+
+// atomUpdated returns the RFC 3339 date of the latest change to the feed.
+func (p *Podcast) atomUpdated() string {
+	if t, err := parsePubDate(p.LastBuildDate); err == nil {
+		return t.Format(time.RFC3339)
+	}
+	var latest time.Time
+	for _, i := range p.Items {
+		if t, err := parsePubDate(i.PubDate); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	if t, err := parsePubDate(p.PubDate); err == nil && t.After(latest) {
+		latest = t
+	}
+	if latest.IsZero() {
+		latest = now()
+	}
+	return latest.Format(time.RFC3339)
+}
+
+func (i *Item) atomEntry(feedUpdated string) *atomEntry {
+	e := &atomEntry{
+		Title:              atomText{Text: i.Title},
+		Updated:            feedUpdated,
+		IAuthor:            i.IAuthor,
+		ITitle:             i.ITitle,
+		SeasonNumber:       i.SeasonNumber,
+		EpisodeNumber:      i.EpisodeNumber,
+		EpisodeType:        i.EpisodeType,
+		ISubtitle:          i.ISubtitle,
+		IImage:             i.IImage,
+		IBlock:             i.IBlock,
+		IDuration:          i.IDuration,
+		IExplicit:          i.IExplicit,
+		IIsClosedCaptioned: i.IIsClosedCaptioned,
+		IOrder:             i.IOrder,
+	}
+	if i.GUID != nil {
+		e.ID = i.GUID.Value
+	} else {
+		e.ID = i.Link
+	}
+	if len(e.ID) == 0 && i.Enclosure != nil {
+		e.ID = i.Enclosure.URL
+	}
+	if t, err := parsePubDate(i.PubDate); err == nil {
+		e.Published = t.Format(time.RFC3339)
+		e.Updated = e.Published
+	}
+	if len(i.Link) != 0 {
+		e.Links = append(e.Links, &atomLink{Href: i.Link, Rel: "alternate"})
+	}
+	if i.Enclosure != nil {
+		length := i.Enclosure.LengthFormatted
+		if len(length) == 0 && i.Enclosure.Length > 0 {
+			length = strconv.FormatInt(i.Enclosure.Length, 10)
+		}
+		e.Links = append(e.Links, &atomLink{
+			Href:   i.Enclosure.URL,
+			Rel:    "enclosure",
+			Type:   i.Enclosure.TypeFormatted,
+			Length: length,
+		})
+	}
+	if len(i.IAuthor) != 0 {
+		e.Author = &atomPerson{Name: i.IAuthor}
+	}
+	for _, c := range i.Categories {
+		e.Category = append(e.Category, &atomTerm{Term: c.Value})
+	}
+	if i.Description != nil && len(i.Description.Text) != 0 {
+		e.Summary = &atomText{Type: "html", Text: i.Description.Text}
+	}
+	if i.EncodedDescription != nil && (e.Summary == nil || i.EncodedDescription.Text != e.Summary.Text) {
+		e.Content = &atomText{Type: "html", Text: i.EncodedDescription.Text}
+	}
+	return e
+}
+
+// now is the clock used when a date has to be made up.
+var now = func() time.Time {
+	return time.Now().UTC()
+}
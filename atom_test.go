@@ -0,0 +1,96 @@
+package podcast_test
+
+import (
+	"bytes"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeAtom(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("Sample Podcasts", "http://example.com/", podcast.Description{Text: "An example Podcast"}, nil, nil)
+	p.AddAtomLink("http://example.com/feed.rss")
+	p.AddAuthor([]string{"Jane Doe"})
+	p.AddImage("http://example.com/podcast.jpg")
+	p.AddCategory("Arts", []string{"Books"})
+	p.AddParentalAdvisory(podcast.ParentalAdvisoryClean)
+
+	i := podcast.Item{Title: "Episode 1", Description: &podcast.Description{Text: "Description for Episode 1"}}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 183)
+	i.AddPubDate("Sun, 14 Mar 2021 18:34:05 +0000")
+	i.AddDuration(1800)
+	if _, err := p.AddItem(i); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	var b bytes.Buffer
+	err := p.EncodeAtom(&b, "http://example.com/feed.atom")
+
+	// assert
+	assert.NoError(t, err)
+	out := b.String()
+	assert.Contains(t, out, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">`)
+	assert.Contains(t, out, `<id>http://example.com/feed.atom</id>`)
+	assert.Contains(t, out, `<updated>2021-03-14T18:34:05Z</updated>`)
+	assert.Contains(t, out, `<link href="http://example.com/" rel="alternate" type="text/html"></link>`)
+	assert.Contains(t, out, `<link href="http://example.com/feed.atom" rel="self" type="application/atom+xml"></link>`)
+	assert.Contains(t, out, `<link href="http://example.com/feed.rss" rel="alternate" type="application/rss+xml"></link>`)
+	assert.NotContains(t, out, `<link href="http://example.com/feed.rss" rel="self"`)
+	assert.Contains(t, out, `<author>`)
+	assert.Contains(t, out, `<itunes:explicit>no</itunes:explicit>`)
+	assert.Contains(t, out, `<itunes:category text="Arts">`)
+	assert.Contains(t, out, `<entry>`)
+	assert.Contains(t, out, `<id>http://example.com/1.mp3</id>`)
+	assert.Contains(t, out, `<published>2021-03-14T18:34:05Z</published>`)
+	assert.Contains(t, out, `<link href="http://example.com/1.mp3" rel="enclosure" type="audio/mpeg" length="183"></link>`)
+	assert.Contains(t, out, `<summary type="html">Description for Episode 1</summary>`)
+	assert.Contains(t, out, `<itunes:duration>1800</itunes:duration>`)
+}
+
+func TestEncodeAtomWriterError(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: ""}, nil, nil)
+
+	err := p.EncodeAtom(&errWriter{}, "http://example.com/feed.atom")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "w.Write return error")
+}
+
+func TestEncodeAtomRequiresURL(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.AddAtomLink("http://example.com/feed.rss")
+
+	// act
+	var b bytes.Buffer
+	err := p.EncodeAtom(&b, "")
+
+	// assert
+	assert.EqualError(t, err, "podcast.EncodeAtom: atomURL is required")
+	assert.Empty(t, b.String())
+}
+
+func TestEncodeAtomRequiresEntryID(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.Items = append(p.Items, &podcast.Item{Title: "Episode 1"})
+
+	// act
+	var b bytes.Buffer
+	err := p.EncodeAtom(&b, "http://example.com/feed.atom")
+
+	// assert
+	assert.EqualError(t, err, "podcast.EncodeAtom: Items[0] has no GUID, Link or Enclosure")
+	assert.Empty(t, b.String())
+}
@@ -3,6 +3,10 @@ package podcast
 import "encoding/xml"
 
 // AtomLink represents the Atom reference link.
+//
+// Rel is "self" for the feed itself, or one of the paging relations
+// RelFirst, RelNext, RelPrev and RelLast for the other documents of a
+// paged feed.
 type AtomLink struct {
 	XMLName xml.Name `xml:"atom:link"`
 	HREF    string   `xml:"href,attr"`
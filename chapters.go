@@ -0,0 +1,123 @@
+package podcast
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: https://github.com/Podcastindex-org/podcast-namespace/blob/main/chapters/jsonChapters.md
+// and https://podlove.org/simple-chapters/
+//
+
+const (
+	// ChaptersMIMEType is the type of the chapters file written by
+	// Item.EncodeChapters, to be used with Item.AddChapters.
+	ChaptersMIMEType = "application/json+chapters"
+
+	// ChaptersVersion is the version of the JSON chapters format.
+	ChaptersVersion = "1.2.0"
+
+	PSCNS = "http://podlove.org/simple-chapters"
+)
+
+// Chapter is a chapter marker of an episode.
+//
+// StartTime is expressed in seconds from the start of the episode.  Img
+// and URL are optional links to the chapter artwork and a web page about
+// the chapter.  Hidden chapters are kept out of the table of contents,
+// e.g. to only change the artwork.
+type Chapter struct {
+	StartTime float64
+	Title     string
+	Img       string
+	URL       string
+	Hidden    bool
+}
+
+// PSCChapters is the Podlove Simple Chapters list written inline in the
+// item for apps that do not read podcast:chapters.
+//
+// The namespace is declared on the element itself so that the channel
+// does not depend on its Items.
+type PSCChapters struct {
+	XMLName  xml.Name `xml:"psc:chapters"`
+	XMLNS    string   `xml:"xmlns:psc,attr"`
+	Version  string   `xml:"version,attr"`
+	Chapters []*PSCChapter
+}
+
+// PSCChapter is a single Podlove chapter.  Start is a normal play time
+// such as 00:01:13.500.
+type PSCChapter struct {
+	XMLName xml.Name `xml:"psc:chapter"`
+	Start   string   `xml:"start,attr"`
+	Title   string   `xml:"title,attr"`
+	Href    string   `xml:"href,attr,omitempty"`
+	Image   string   `xml:"image,attr,omitempty"`
+}
+
+type chaptersJSON struct {
+	Version  string         `json:"version"`
+	Chapters []*chapterJSON `json:"chapters"`
+}
+
+type chapterJSON struct {
+	StartTime float64 `json:"startTime"`
+	Title     string  `json:"title,omitempty"`
+	Img       string  `json:"img,omitempty"`
+	URL       string  `json:"url,omitempty"`
+	TOC       *bool   `json:"toc,omitempty"`
+}
+
+// EncodeChapters writes the chapters of the Item to the io.Writer stream
+// as a Podcasting 2.0 JSON chapters file, ordered by start time.
+//
+// Host the file and link it with AddChapters(url, ChaptersMIMEType).
+func (i *Item) EncodeChapters(w io.Writer) error {
+	doc := chaptersJSON{
+		Version:  ChaptersVersion,
+		Chapters: []*chapterJSON{},
+	}
+	for _, c := range i.sortedChapters() {
+		jc := &chapterJSON{
+			StartTime: c.StartTime,
+			Title:     c.Title,
+			Img:       c.Img,
+			URL:       c.URL,
+		}
+		if c.Hidden {
+			toc := false
+			jc.TOC = &toc
+		}
+		doc.Chapters = append(doc.Chapters, jc)
+	}
+
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return errors.Wrap(err, "podcast.EncodeChapters: e.Encode returned error")
+	}
+	return nil
+}
+
+// sortedChapters returns the chapters ordered by start time, keeping the
+// order in which they were added for equal times.
+func (i *Item) sortedChapters() []*Chapter {
+	chapters := append([]*Chapter{}, i.Chapters...)
+	sort.SliceStable(chapters, func(a, b int) bool {
+		return chapters[a].StartTime < chapters[b].StartTime
+	})
+	return chapters
+}
+
+// chapterOutOfBounds reports whether the chapter starts after the end of
+// the episode, when the IDuration is known.
+func (i *Item) chapterOutOfBounds(c *Chapter) bool {
+	d, err := durationSeconds(i.IDuration)
+	return err == nil && d > 0 && c.StartTime >= float64(d)
+}
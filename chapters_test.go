@@ -0,0 +1,174 @@
+package podcast_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func newChaptersItem(t *testing.T) podcast.Item {
+	t.Helper()
+	i := podcast.Item{Title: "Episode 1"}
+	i.AddDuration(1800)
+	for _, c := range []podcast.Chapter{
+		{StartTime: 73.5, Title: "News & Updates", URL: "http://example.com/news"},
+		{StartTime: 0, Title: "Intro", Img: "http://example.com/intro.jpg"},
+		{StartTime: 600, Title: "Sponsor", Hidden: true},
+	} {
+		if err := i.AddChapter(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return i
+}
+
+func TestEncodeChapters(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	i := newChaptersItem(t)
+
+	// act
+	var b bytes.Buffer
+	err := i.EncodeChapters(&b)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "version": "1.2.0",
+  "chapters": [
+    {
+      "startTime": 0,
+      "title": "Intro",
+      "img": "http://example.com/intro.jpg"
+    },
+    {
+      "startTime": 73.5,
+      "title": "News & Updates",
+      "url": "http://example.com/news"
+    },
+    {
+      "startTime": 600,
+      "title": "Sponsor",
+      "toc": false
+    }
+  ]
+}
+`, b.String())
+}
+
+func TestEncodeChaptersEmpty(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	var b bytes.Buffer
+	err := i.EncodeChapters(&b)
+
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `"chapters": []`)
+}
+
+func TestAddChapterInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		chapter podcast.Chapter
+	}{
+		{"no title", podcast.Chapter{StartTime: 1}},
+		{"negative start", podcast.Chapter{StartTime: -1, Title: "Intro"}},
+		{"beyond duration", podcast.Chapter{StartTime: 1800, Title: "Outro"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			i := podcast.Item{}
+			i.AddDuration(1800)
+
+			err := i.AddChapter(tt.chapter)
+
+			assert.Error(t, err)
+			assert.Len(t, i.Chapters, 0)
+		})
+	}
+}
+
+func TestAddChapterWithoutDuration(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	err := i.AddChapter(podcast.Chapter{StartTime: 7200, Title: "Late"})
+
+	assert.NoError(t, err)
+	assert.Len(t, i.Chapters, 1)
+}
+
+func TestAddPodloveChapters(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	i := newChaptersItem(t)
+
+	// act
+	i.AddPodloveChapters()
+	b, err := xml.MarshalIndent(i.PSCChapters, "", "  ")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, `<psc:chapters xmlns:psc="http://podlove.org/simple-chapters" version="1.2">
+  <psc:chapter start="00:00:00.000" title="Intro" image="http://example.com/intro.jpg"></psc:chapter>
+  <psc:chapter start="00:01:13.500" title="News &amp; Updates" href="http://example.com/news"></psc:chapter>
+</psc:chapters>`, string(b))
+}
+
+func TestAddPodloveChaptersEmpty(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddPodloveChapters()
+
+	assert.Nil(t, i.PSCChapters)
+}
+
+func TestChaptersReferencedFromFeed(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, nil, nil)
+	i := newChaptersItem(t)
+	i.Link = "http://example.com/1"
+	i.Description = &podcast.Description{Text: "Description"}
+	i.AddChapters("http://example.com/1.json", podcast.ChaptersMIMEType)
+
+	// act
+	_, err := p.AddItem(i)
+	out := p.String()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out, `<podcast:chapters url="http://example.com/1.json" type="application/json+chapters"></podcast:chapters>`)
+	assert.NotContains(t, out, "Sponsor")
+}
+
+func TestValidateChaptersBounds(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newValidPodcast(t)
+	p.Items[0].Chapters = []*podcast.Chapter{{StartTime: 1e6, Title: "Late"}}
+	p.Items[0].AddDuration(60)
+
+	// act
+	findings := p.Validate(podcast.PodcastIndexProfile)
+
+	// assert
+	assert.Contains(t, findingRules(findings), "podcastindex.item.chapters.bounds")
+}
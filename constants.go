@@ -7,4 +7,6 @@ const (
 	EpisodeTypeFull          = "full"
 	EpisodeTypeTrailer       = "trailer"
 	EpisodeTypeBonus         = "bonus"
+	ShowTypeEpisodic         = "episodic"
+	ShowTypeSerial           = "serial"
 )
@@ -0,0 +1,571 @@
+package podcast
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/georgboe/rss-feed-generator/parser"
+	ext "github.com/georgboe/rss-feed-generator/parser/extensions"
+	"github.com/pkg/errors"
+)
+
+// Rule IDs of the Findings reported by NewFromFeed.
+const (
+	RuleConvertUnsupported = "convert.unsupported"
+	RuleConvertInvalid     = "convert.invalid"
+	RuleConvertExtra       = "convert.extra"
+)
+
+// extension prefixes that are read through their typed parser.Feed
+// counterparts rather than through Extensions.
+var convertedExtensions = map[string]bool{
+	"itunes":  true,
+	"atom":    true,
+	"atom10":  true,
+	"atom03":  true,
+	"content": true,
+	"dc":      true,
+}
+
+// NewFromFeed builds a Podcast, its Items and their Enclosures from a feed
+// parsed with parser.Parser, including the ITunesExt and the known
+// "podcast" and "googleplay" Extensions.
+//
+// Fields are copied as-is onto the structs so that a parse → generate
+// round-trip does not alter the content.  Items are appended directly
+// rather than through AddItem, which would override GUIDs and enclosure
+// types.  Extension elements that have no converter are kept as
+// ExtensionElements when their namespace is registered, see
+// RegisterExtensionNamespace; those that have one but are invalid are
+// dropped.
+//
+// Everything that could not be carried across, such as Dublin Core
+// metadata, unknown extensions or additional enclosures, is reported as a
+// SeverityWarning Finding whose Field is the path in the parser.Feed.
+func NewFromFeed(feed *parser.Feed) (*Podcast, Findings, error) {
+	if feed == nil {
+		return nil, nil, errors.New("podcast.NewFromFeed: feed is nil")
+	}
+
+	c := &feedConverter{}
+	p := c.podcast(feed)
+	for n, item := range feed.Items {
+		p.Items = append(p.Items, c.item(fmt.Sprintf("Items[%d]", n), item))
+	}
+	return p, c.findings, nil
+}
+
+// feedConverter collects the Findings while converting a parser.Feed.
+type feedConverter struct {
+	findings Findings
+}
+
+func (c *feedConverter) lost(rule, field, message string) {
+	c.findings = append(c.findings, Finding{
+		Severity: SeverityWarning,
+		Field:    field,
+		Rule:     rule,
+		Message:  message,
+	})
+}
+
+func (c *feedConverter) podcast(feed *parser.Feed) *Podcast {
+	p := &Podcast{
+		Title:         feed.Title,
+		Link:          feed.Link,
+		Language:      feed.Language,
+		Copyright:     feed.Copyright,
+		Generator:     feed.Generator,
+		PubDate:       convertDate(feed.Published, feed.PublishedParsed),
+		LastBuildDate: convertDate(feed.Updated, feed.UpdatedParsed),
+		encode:        encoder,
+	}
+	if len(feed.Description) != 0 {
+		p.Description = &Description{Text: feed.Description}
+	}
+	p.AddAtomLink(feed.FeedLink)
+	if feed.Image != nil && len(feed.Image.URL) != 0 {
+		p.Image = &Image{URL: feed.Image.URL, Title: feed.Image.Title, Link: feed.Link}
+		p.IImage = &IImage{HREF: feed.Image.URL}
+	}
+
+	keywords := c.podcastITunes(p, feed.ITunesExt)
+	c.authors("Authors", feed.Authors, &p.ManagingEditor, &p.IAuthor)
+	for n, cat := range feed.Categories {
+		if !keywords[cat] {
+			c.lost(RuleConvertUnsupported, fmt.Sprintf("Categories[%d]", n), "channel category "+cat+" has no Podcast field")
+		}
+	}
+	if feed.DublinCoreExt != nil {
+		c.lost(RuleConvertUnsupported, "DublinCoreExt", "Dublin Core metadata is not supported")
+	}
+	for _, key := range sortedCustomKeys(feed.Custom) {
+		c.lost(RuleConvertUnsupported, "Custom."+key, "custom elements are not supported")
+	}
+	p.Extensions = c.extensions("Extensions", feed.Extensions, map[string]func(ext.Extension) extensionConversion{
+		"podcast":    func(e ext.Extension) extensionConversion { return podcastIndexExtension(p, e) },
+		"googleplay": func(e ext.Extension) extensionConversion { return googlePlayExtension(p, e) },
+	})
+	return p
+}
+
+// podcastITunes copies the iTunes channel tags and returns the category
+// texts and keywords the parser merged into Feed.Categories.
+func (c *feedConverter) podcastITunes(p *Podcast, it *ext.ITunesFeedExtension) map[string]bool {
+	known := make(map[string]bool)
+	if it == nil {
+		return known
+	}
+
+	p.IAuthor = it.Author
+	p.IBlock = it.Block
+	p.IExplicit = it.Explicit
+	p.ISubtitle = it.Subtitle
+	p.IComplete = it.Complete
+	p.INewFeedURL = it.NewFeedURL
+	p.IType = it.Type
+	if len(it.Summary) != 0 {
+		p.ISummary = &ISummary{Text: it.Summary}
+	}
+	if len(it.Image) != 0 {
+		p.IImage = &IImage{HREF: it.Image}
+	}
+	if it.Owner != nil {
+		p.IOwner = &Author{Name: it.Owner.Name, Email: it.Owner.Email}
+	}
+	for _, cat := range it.Categories {
+		icat := &ICategory{Text: cat.Text}
+		known[cat.Text] = true
+		if cat.Subcategory != nil {
+			icat.ICategories = append(icat.ICategories, &ICategory{Text: cat.Subcategory.Text})
+			known[cat.Subcategory.Text] = true
+		}
+		p.ICategories = append(p.ICategories, icat)
+	}
+	if len(it.Keywords) != 0 {
+		c.lost(RuleConvertUnsupported, "ITunesExt.Keywords", "itunes:keywords is deprecated and not supported")
+		for _, k := range strings.Split(it.Keywords, ",") {
+			known[k] = true
+		}
+	}
+	return known
+}
+
+func (c *feedConverter) item(path string, item *parser.Item) *Item {
+	i := &Item{
+		Title:   item.Title,
+		Link:    item.Link,
+		PubDate: convertDate(item.Published, item.PublishedParsed),
+	}
+	if len(item.Description) != 0 {
+		i.Description = &Description{Text: item.Description}
+	}
+	if len(item.Content) != 0 {
+		i.EncodedDescription = &EncodedContent{Text: item.Content}
+	}
+	if len(item.Updated) != 0 && item.Updated != item.Published {
+		c.lost(RuleConvertUnsupported, path+".Updated", "item updated date "+item.Updated+" has no Item field")
+	}
+	if item.Image != nil && len(item.Image.URL) != 0 {
+		i.IImage = &IImage{HREF: item.Image.URL}
+	}
+	for n, e := range item.Enclosures {
+		if n == 0 {
+			i.Enclosure = c.enclosure(fmt.Sprintf("%s.Enclosures[%d]", path, n), e)
+			continue
+		}
+		c.lost(RuleConvertExtra, fmt.Sprintf("%s.Enclosures[%d]", path, n), "only the first enclosure is kept")
+	}
+	if len(item.GUID) != 0 {
+		isPermaLink := item.GUID == item.Link || (i.Enclosure != nil && item.GUID == i.Enclosure.URL)
+		i.GUID = &GUID{IsPermaLink: isPermaLink, Value: item.GUID}
+	}
+
+	keywords := c.itemITunes(path, i, item.ITunesExt)
+	c.authors(path+".Authors", item.Authors, &i.AuthorFormatted, &i.IAuthor)
+	// the parser rewrites the itunes:duration as seconds
+	if d := extensionValue(item.Extensions, "itunes", "duration"); len(d) != 0 {
+		i.IDuration = d
+	}
+	for _, cat := range item.Categories {
+		if !keywords[cat] {
+			i.AddCategory(cat, "")
+		}
+	}
+	if item.DublinCoreExt != nil {
+		c.lost(RuleConvertUnsupported, path+".DublinCoreExt", "Dublin Core metadata is not supported")
+	}
+	for _, key := range sortedCustomKeys(item.Custom) {
+		c.lost(RuleConvertUnsupported, path+".Custom."+key, "custom elements are not supported")
+	}
+	i.Extensions = c.extensions(path+".Extensions", item.Extensions, map[string]func(ext.Extension) extensionConversion{
+		"podcast":    func(e ext.Extension) extensionConversion { return podcastIndexItemExtension(i, e) },
+		"googleplay": func(e ext.Extension) extensionConversion { return googlePlayItemExtension(i, e) },
+	})
+	return i
+}
+
+// authors keeps the first author as the formatted author when it has an
+// email, and otherwise as the iTunes author unless that is another name.
+// The authors that are not kept are reported.
+func (c *feedConverter) authors(path string, authors []*parser.Person, formatted, itunes *string) {
+	for n, a := range authors {
+		switch {
+		case n == 0 && len(a.Email) != 0:
+			*formatted = parseAuthorNameEmail(&Author{Name: a.Name, Email: a.Email})
+			continue
+		case len(a.Email) == 0 && len(a.Name) != 0 && (len(*itunes) == 0 || *itunes == a.Name):
+			*itunes = a.Name
+			continue
+		}
+		c.lost(RuleConvertUnsupported, fmt.Sprintf("%s[%d]", path, n),
+			"author "+strings.TrimSpace(a.Name+" "+a.Email)+" is not kept")
+	}
+}
+
+// itemITunes copies the iTunes item tags and returns the keywords the
+// parser merged into Item.Categories.
+func (c *feedConverter) itemITunes(path string, i *Item, it *ext.ITunesItemExtension) map[string]bool {
+	keywords := make(map[string]bool)
+	if it == nil {
+		return keywords
+	}
+
+	i.IAuthor = it.Author
+	i.IBlock = it.Block
+	i.IExplicit = it.Explicit
+	i.ISubtitle = it.Subtitle
+	i.IIsClosedCaptioned = it.IsClosedCaptioned
+	i.EpisodeNumber = it.Episode
+	i.SeasonNumber = it.Season
+	i.IOrder = it.Order
+	i.EpisodeType = it.EpisodeType
+	if it.Duration != "0" {
+		i.IDuration = it.Duration
+	}
+	if len(it.Summary) != 0 {
+		i.ISummary = &ISummary{Text: it.Summary}
+	}
+	if len(it.Image) != 0 {
+		i.IImage = &IImage{HREF: it.Image}
+	}
+	if len(it.Keywords) != 0 {
+		c.lost(RuleConvertUnsupported, path+".ITunesExt.Keywords", "itunes:keywords is deprecated and not supported")
+		for _, k := range strings.Split(it.Keywords, ",") {
+			keywords[k] = true
+		}
+	}
+	return keywords
+}
+
+func (c *feedConverter) enclosure(path string, e *parser.Enclosure) *Enclosure {
+	et, err := EnclosureTypeByMIME(e.Type)
+	if err != nil {
+		c.lost(RuleConvertInvalid, path+".Type", "type "+e.Type+" is not a registered enclosure type")
+	}
+	enclosure := &Enclosure{
+		URL:             e.URL,
+		Type:            et,
+		TypeFormatted:   e.Type,
+		LengthFormatted: e.Length,
+	}
+	if len(e.Length) != 0 {
+		length, err := strconv.ParseInt(e.Length, 10, 64)
+		if err != nil || length < 0 {
+			c.lost(RuleConvertInvalid, path+".Length", "length "+e.Length+" is not a byte count")
+			length = 0
+		}
+		enclosure.Length = length
+		enclosure.LengthFormatted = strconv.FormatInt(length, 10)
+	}
+	return enclosure
+}
+
+// extensionConversion is the outcome of converting an extension element.
+type extensionConversion int
+
+const (
+	// extensionUnsupported means there is no converter for the element.
+	extensionUnsupported extensionConversion = iota
+	extensionConverted
+	// extensionInvalid means the element has a converter but could not
+	// be converted.
+	extensionInvalid
+)
+
+// extensions hands every extension element to the converter registered
+// for its prefix.  Those without a converter for their prefix and name
+// are returned as ExtensionElements when their namespace is known; the
+// others that were not converted are reported.
+func (c *feedConverter) extensions(path string, extensions ext.Extensions, converters map[string]func(ext.Extension) extensionConversion) []*ExtensionElement {
+	var kept []*ExtensionElement
+	for _, prefix := range sortedKeys(extensions) {
+		if convertedExtensions[prefix] {
+			continue
+		}
+		convert := converters[prefix]
+		elements := extensions[prefix]
+		names := make([]string, 0, len(elements))
+		for name := range elements {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for n, e := range elements[name] {
+				result := extensionUnsupported
+				if convert != nil {
+					result = convert(e)
+				}
+				if result == extensionConverted {
+					continue
+				}
+				field := fmt.Sprintf("%s.%s.%s[%d]", path, prefix, name, n)
+				if result == extensionInvalid {
+					c.lost(RuleConvertInvalid, field, "extension element "+prefix+":"+name+" has an invalid value")
+					continue
+				}
+				if _, ok := extensionNamespace(prefix); ok {
+					kept = append(kept, &ExtensionElement{Prefix: prefix, Extension: e})
+					continue
+				}
+				c.lost(RuleConvertUnsupported, field, "extension element "+prefix+":"+name+" is not supported")
+			}
+		}
+	}
+	return kept
+}
+
+// extensionValue returns the text of the first prefix:name element.
+func extensionValue(extensions ext.Extensions, prefix, name string) string {
+	if elements := extensions[prefix][name]; len(elements) != 0 {
+		return elements[0].Value
+	}
+	return ""
+}
+
+func sortedKeys(extensions ext.Extensions) []string {
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedCustomKeys(custom map[string]string) []string {
+	keys := make([]string, 0, len(custom))
+	for key := range custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// podcastIndexExtension converts the channel level podcast:* elements.
+func podcastIndexExtension(p *Podcast, e ext.Extension) extensionConversion {
+	switch e.Name {
+	case "guid":
+		p.PGUID = &PodcastGUID{Value: e.Value}
+	case "locked":
+		p.PLocked = &PodcastLocked{Owner: e.Attrs["owner"], Value: e.Value}
+	case "funding":
+		p.PFunding = append(p.PFunding, &PodcastFunding{URL: e.Attrs["url"], Text: e.Value})
+	case "person":
+		p.PPersons = append(p.PPersons, podcastPerson(e))
+	case "location":
+		p.PLocation = podcastLocation(e)
+	case "value":
+		value, ok := podcastValue(e)
+		if !ok {
+			return extensionInvalid
+		}
+		p.PValue = value
+	default:
+		return extensionUnsupported
+	}
+	return extensionConverted
+}
+
+// podcastIndexItemExtension converts the item level podcast:* elements.
+func podcastIndexItemExtension(i *Item, e ext.Extension) extensionConversion {
+	switch e.Name {
+	case "transcript":
+		i.PTranscripts = append(i.PTranscripts, &PodcastTranscript{
+			URL:      e.Attrs["url"],
+			Type:     e.Attrs["type"],
+			Language: e.Attrs["language"],
+			Rel:      e.Attrs["rel"],
+		})
+	case "chapters":
+		i.PChapters = &PodcastChapters{URL: e.Attrs["url"], Type: e.Attrs["type"]}
+	case "soundbite":
+		start, err1 := strconv.ParseFloat(e.Attrs["startTime"], 64)
+		duration, err2 := strconv.ParseFloat(e.Attrs["duration"], 64)
+		if err1 != nil || err2 != nil {
+			return extensionInvalid
+		}
+		i.PSoundbites = append(i.PSoundbites, &PodcastSoundbite{StartTime: start, Duration: duration, Title: e.Value})
+	case "person":
+		i.PPersons = append(i.PPersons, podcastPerson(e))
+	case "location":
+		i.PLocation = podcastLocation(e)
+	case "season":
+		number, err := strconv.ParseInt(strings.TrimSpace(e.Value), 10, 64)
+		if err != nil {
+			return extensionInvalid
+		}
+		i.PSeason = &PodcastSeason{Number: number, Name: e.Attrs["name"]}
+	case "episode":
+		number, err := strconv.ParseFloat(strings.TrimSpace(e.Value), 64)
+		if err != nil {
+			return extensionInvalid
+		}
+		i.PEpisode = &PodcastEpisode{Number: number, Display: e.Attrs["display"]}
+	case "value":
+		value, ok := podcastValue(e)
+		if !ok {
+			return extensionInvalid
+		}
+		i.PValue = value
+	case "alternateEnclosure":
+		ae, ok := podcastAlternateEnclosure(e)
+		if !ok {
+			return extensionInvalid
+		}
+		i.PAlternateEnclosures = append(i.PAlternateEnclosures, ae)
+	default:
+		return extensionUnsupported
+	}
+	return extensionConverted
+}
+
+// podcastValue converts a podcast:value block, which is only kept when
+// it is valid.
+func podcastValue(e ext.Extension) (*PodcastValue, bool) {
+	value := &PodcastValue{
+		Type:      e.Attrs["type"],
+		Method:    e.Attrs["method"],
+		Suggested: e.Attrs["suggested"],
+	}
+	for _, r := range e.Children["valueRecipient"] {
+		split, err := strconv.Atoi(strings.TrimSpace(r.Attrs["split"]))
+		if err != nil {
+			return nil, false
+		}
+		value.Recipients = append(value.Recipients, &PodcastValueRecipient{
+			Name:        r.Attrs["name"],
+			CustomKey:   r.Attrs["customKey"],
+			CustomValue: r.Attrs["customValue"],
+			Type:        r.Attrs["type"],
+			Address:     r.Attrs["address"],
+			Split:       split,
+			Fee:         r.Attrs["fee"] == "true",
+		})
+	}
+	if value.Validate() != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// podcastAlternateEnclosure converts a podcast:alternateEnclosure, which
+// is only kept when it has a type and a source.
+func podcastAlternateEnclosure(e ext.Extension) (*PodcastAlternateEnclosure, bool) {
+	ae := &PodcastAlternateEnclosure{
+		Type:    e.Attrs["type"],
+		Lang:    e.Attrs["lang"],
+		Title:   e.Attrs["title"],
+		Rel:     e.Attrs["rel"],
+		Codecs:  e.Attrs["codecs"],
+		Default: e.Attrs["default"] == "true",
+	}
+	ae.Length, _ = strconv.ParseInt(e.Attrs["length"], 10, 64)
+	ae.Bitrate, _ = strconv.ParseFloat(e.Attrs["bitrate"], 64)
+	ae.Height, _ = strconv.Atoi(e.Attrs["height"])
+	for _, s := range e.Children["source"] {
+		ae.AddSource(s.Attrs["uri"], s.Attrs["contentType"])
+	}
+	for _, in := range e.Children["integrity"] {
+		ae.AddIntegrity(in.Attrs["type"], in.Attrs["value"])
+	}
+	if len(ae.Type) == 0 || len(ae.Sources) == 0 {
+		return nil, false
+	}
+	return ae, true
+}
+
+func podcastPerson(e ext.Extension) *PodcastPerson {
+	return &PodcastPerson{
+		Name:  e.Value,
+		Role:  e.Attrs["role"],
+		Group: e.Attrs["group"],
+		Img:   e.Attrs["img"],
+		Href:  e.Attrs["href"],
+	}
+}
+
+func podcastLocation(e ext.Extension) *PodcastLocation {
+	return &PodcastLocation{Name: e.Value, Geo: e.Attrs["geo"], OSM: e.Attrs["osm"]}
+}
+
+// googlePlayExtension converts the channel level googleplay:* elements.
+func googlePlayExtension(p *Podcast, e ext.Extension) extensionConversion {
+	switch e.Name {
+	case "author":
+		p.GooglePlayAuthor = e.Value
+	case "description":
+		p.GooglePlayDescription = e.Value
+	case "owner", "email":
+		p.GooglePlayOwner = e.Value
+	case "image":
+		p.GooglePlayImage = &GooglePlayImage{HREF: e.Attrs["href"]}
+	case "category":
+		p.GooglePlayCategories = append(p.GooglePlayCategories, googlePlayCategoryExtension(e))
+	case "explicit":
+		p.GooglePlayExplicit = e.Value
+	case "block":
+		p.GooglePlayBlock = e.Value
+	default:
+		return extensionUnsupported
+	}
+	return extensionConverted
+}
+
+// googlePlayItemExtension converts the item level googleplay:* elements.
+func googlePlayItemExtension(i *Item, e ext.Extension) extensionConversion {
+	switch e.Name {
+	case "author":
+		i.GooglePlayAuthor = e.Value
+	case "description":
+		i.GooglePlayDescription = e.Value
+	case "image":
+		i.GooglePlayImage = &GooglePlayImage{HREF: e.Attrs["href"]}
+	case "explicit":
+		i.GooglePlayExplicit = e.Value
+	case "block":
+		i.GooglePlayBlock = e.Value
+	default:
+		return extensionUnsupported
+	}
+	return extensionConverted
+}
+
+func googlePlayCategoryExtension(e ext.Extension) *GooglePlayCategory {
+	gcat := &GooglePlayCategory{Text: e.Attrs["text"]}
+	for _, sub := range e.Children["category"] {
+		gcat.GooglePlayCategories = append(gcat.GooglePlayCategories, googlePlayCategoryExtension(sub))
+	}
+	return gcat
+}
+
+// convertDate prefers the parsed date, formatted as RFC 2822, over the
+// raw string the parser could not make sense of.
+func convertDate(raw string, parsed *time.Time) string {
+	if parsed != nil {
+		return parsed.Format(time.RFC1123Z)
+	}
+	return raw
+}
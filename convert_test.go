@@ -0,0 +1,94 @@
+package podcast_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/georgboe/rss-feed-generator/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the .golden files")
+
+func TestNewFromFeedGolden(t *testing.T) {
+	files, _ := filepath.Glob("testdata/convert/*.xml")
+	for _, f := range files {
+		name := strings.TrimSuffix(f, filepath.Ext(f))
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			// arrange
+			in, err := os.Open(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer in.Close()
+			feed, err := parser.NewParser().Parse(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// act
+			p, findings, err := podcast.NewFromFeed(feed)
+			assert.NoError(t, err)
+			var b bytes.Buffer
+			assert.NoError(t, p.Encode(&b))
+			for _, finding := range findings {
+				b.WriteString("\n<!-- " + finding.String() + " -->")
+			}
+			b.WriteString("\n")
+
+			// assert
+			golden := name + ".golden"
+			if *update {
+				if err := ioutil.WriteFile(golden, b.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, _ := ioutil.ReadFile(golden)
+			assert.Equal(t, string(expected), b.String())
+		})
+	}
+}
+
+func TestNewFromFeedNil(t *testing.T) {
+	t.Parallel()
+
+	p, findings, err := podcast.NewFromFeed(nil)
+
+	assert.Nil(t, p)
+	assert.Nil(t, findings)
+	assert.Error(t, err)
+}
+
+func TestNewFromFeedAuthors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	feed := &parser.Feed{
+		Title:   "title",
+		Authors: []*parser.Person{{Name: "Jane Doe"}, {Name: "John Doe", Email: "john@example.com"}},
+		Items: []*parser.Item{{
+			Title:     "Episode 1",
+			Authors:   []*parser.Person{{Name: "Jane Doe", Email: "jane@example.com"}},
+			Published: "Mon, 15 Mar 2021 10:00:00 +0000",
+			Updated:   "2021-03-16T10:00:00Z",
+		}},
+	}
+
+	// act
+	p, findings, err := podcast.NewFromFeed(feed)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", p.IAuthor)
+	assert.Equal(t, "jane@example.com (Jane Doe)", p.Items[0].AuthorFormatted)
+	assert.Len(t, findings, 2)
+	assert.Equal(t, "Authors[1]", findings[0].Field)
+	assert.Equal(t, podcast.RuleConvertUnsupported, findings[0].Rule)
+	assert.Equal(t, "Items[0].Updated", findings[1].Field)
+}
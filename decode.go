@@ -0,0 +1,107 @@
+package podcast
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/georgboe/rss-feed-generator/parser"
+	"github.com/georgboe/rss-feed-generator/parser/rss"
+	"github.com/pkg/errors"
+)
+
+// Decode reads an RSS 2.0 document, such as one written by Encode, into a
+// Podcast so that it can be edited and encoded again.
+//
+// The channel and items are converted as with NewFromFeed, and the RSS
+// elements the universal parser.Feed does not carry, such as cloud,
+// skipHours, textInput or the item categories with their domain, are
+// copied from the RSS document.  Enclosure.Type is looked up from the
+// MIME type in Enclosure.TypeFormatted and Enclosure.LengthFormatted is
+// the decimal Enclosure.Length, so encode → decode → encode is stable.
+//
+// Elements that cannot be carried across are dropped.  Use NewFromFeed
+// for a report of them.
+func Decode(r io.Reader) (*Podcast, error) {
+	feed, err := (&rss.Parser{}).Parse(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.Decode")
+	}
+	universal, err := (&parser.DefaultRSSTranslator{}).Translate(feed)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.Decode")
+	}
+	p, _, err := NewFromFeed(universal)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.Decode")
+	}
+
+	decodeChannel(p, feed)
+	for n, item := range feed.Items {
+		if n < len(p.Items) {
+			decodeItem(p.Items[n], item)
+		}
+	}
+	return p, nil
+}
+
+// decodeChannel copies the RSS channel elements that NewFromFeed does not
+// convert.
+func decodeChannel(p *Podcast, feed *rss.Feed) {
+	p.ManagingEditor = feed.ManagingEditor
+	p.WebMaster = feed.WebMaster
+	p.Docs = feed.Docs
+	p.Rating = feed.Rating
+	if ttl, err := strconv.Atoi(strings.TrimSpace(feed.TTL)); err == nil {
+		p.TTL = ttl
+	}
+	if feed.Image != nil && p.Image != nil {
+		p.Image.Link = feed.Image.Link
+		p.Image.Description = feed.Image.Description
+		p.Image.Width, _ = strconv.Atoi(strings.TrimSpace(feed.Image.Width))
+		p.Image.Height, _ = strconv.Atoi(strings.TrimSpace(feed.Image.Height))
+	}
+	if t := feed.TextInput; t != nil {
+		p.TextInput = &TextInput{Title: t.Title, Description: t.Description, Name: t.Name, Link: t.Link}
+	}
+	if c := feed.Cloud; c != nil {
+		port, _ := strconv.Atoi(strings.TrimSpace(c.Port))
+		p.Cloud = &Cloud{
+			Domain:            c.Domain,
+			Port:              port,
+			Path:              c.Path,
+			RegisterProcedure: c.RegisterProcedure,
+			Protocol:          c.Protocol,
+		}
+	}
+	for _, h := range feed.SkipHours {
+		if hour, err := strconv.Atoi(strings.TrimSpace(h)); err == nil {
+			if p.SkipHours == nil {
+				p.SkipHours = &SkipHours{}
+			}
+			p.SkipHours.Hours = append(p.SkipHours.Hours, hour)
+		}
+	}
+	if len(feed.SkipDays) != 0 {
+		p.SkipDays = &SkipDays{Days: feed.SkipDays}
+	}
+	for _, l := range feed.Extensions["atom"]["link"] {
+		if rel := l.Attrs["rel"]; rel != "self" {
+			p.AddPagingLink(rel, l.Attrs["href"])
+		}
+	}
+}
+
+// decodeItem copies the RSS item elements that NewFromFeed does not
+// convert.
+func decodeItem(i *Item, item *rss.Item) {
+	i.AuthorFormatted = item.Author
+	i.Comments = item.Comments
+	i.Categories = nil
+	for _, c := range item.Categories {
+		i.AddCategory(c.Value, c.Domain)
+	}
+	if s := item.Source; s != nil {
+		i.Source = &Source{URL: s.URL, Title: s.Title}
+	}
+}
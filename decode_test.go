@@ -0,0 +1,131 @@
+package podcast_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDecodePodcast(t *testing.T) podcast.Podcast {
+	t.Helper()
+	p := newValidPodcast(t)
+	p.AddAtomLink("http://example.com/feed.rss")
+	p.AddPagingLink(podcast.RelNext, "http://example.com/feed.rss?page=2")
+	p.AddSubTitle("A sample")
+	p.AddSummary("An <b>example</b> podcast")
+	p.AddCopyright("2021 Example Inc")
+	p.AddItunesType("serial")
+	p.AddPodcastGUID("917393e3-1b1e-5cef-ace4-edaa54e1f810")
+	p.AddLocked(true, "jane@example.com")
+	p.AddRating("(PICS-1.1 \"http://www.rsac.org/ratingsv01.html\" l r (n 0))")
+	p.ManagingEditor = "jane@example.com (Jane Doe)"
+	p.WebMaster = "web@example.com (Web Master)"
+	p.Docs = "https://www.rssboard.org/rss-specification"
+	p.TTL = 60
+	p.TextInput = &podcast.TextInput{Title: "Search", Description: "Search the show", Name: "q", Link: "http://example.com/search"}
+	assert.NoError(t, p.AddCloud("rpc.example.com", 80, "/RPC2", "pingMe", podcast.CloudXMLRPC))
+	assert.NoError(t, p.AddSkipHours(0, 1))
+	assert.NoError(t, p.AddSkipDays("Saturday", "Sunday"))
+
+	i := podcast.Item{Title: "Episode 2", Description: &podcast.Description{Text: "Description for <i>Episode 2</i>"}}
+	i.AddEnclosure("http://example.com/2.m4a", podcast.M4A, "", 2000)
+	i.AddPubDate("Mon, 15 Mar 2021 10:00:00 +0000")
+	i.AddDuration(3723)
+	i.AddSeasonNumber(1)
+	i.AddEpisodeNumber(2)
+	i.AddCategory("Tech", "http://example.com/taxonomy")
+	i.AddSource("Other Feed", "http://example.com/other.xml")
+	i.AddComments("http://example.com/2#comments")
+	i.AddTranscript("http://example.com/2.vtt", "text/vtt", "en", "captions")
+	_, err := p.AddItem(i)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newDecodePodcast(t)
+	var first bytes.Buffer
+	assert.NoError(t, p.Encode(&first))
+
+	// act
+	decoded, err := podcast.Decode(bytes.NewReader(first.Bytes()))
+	assert.NoError(t, err)
+	var second bytes.Buffer
+	assert.NoError(t, decoded.Encode(&second))
+
+	// assert
+	assert.Equal(t, first.String(), second.String())
+	assert.Equal(t, podcast.M4A, decoded.Items[1].Enclosure.Type)
+	assert.Equal(t, int64(2000), decoded.Items[1].Enclosure.Length)
+	assert.Equal(t, "2000", decoded.Items[1].Enclosure.LengthFormatted)
+	assert.Equal(t, "audio/x-m4a", decoded.Items[1].Enclosure.TypeFormatted)
+	assert.Equal(t, []int{0, 1}, decoded.SkipHours.Hours)
+	assert.Equal(t, "http://example.com/taxonomy", decoded.Items[1].Categories[0].Domain)
+}
+
+func TestDecodeAppendItem(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newDecodePodcast(t)
+	var b bytes.Buffer
+	assert.NoError(t, p.Encode(&b))
+	decoded, err := podcast.Decode(&b)
+	assert.NoError(t, err)
+	i := podcast.Item{Title: "Episode 3", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/3.mp3", podcast.MP3, "", 3000)
+	i.SetPubDate(time.Date(2021, 3, 16, 10, 0, 0, 0, time.UTC))
+
+	// act
+	n, err := decoded.AddItem(i)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "Tue, 16 Mar 2021 10:00:00 +0000", decoded.LastBuildDate)
+	assert.Equal(t, "Jane Doe", decoded.Items[2].IAuthor)
+	assert.Equal(t, "http://example.com/i.jpg", decoded.Items[2].IImage.HREF)
+}
+
+func TestDecodeStable(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	in, err := os.Open("testdata/convert/podcast.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	p, err := podcast.Decode(in)
+	assert.NoError(t, err)
+	var first bytes.Buffer
+	assert.NoError(t, p.Encode(&first))
+
+	// act
+	decoded, err := podcast.Decode(bytes.NewReader(first.Bytes()))
+	assert.NoError(t, err)
+	var second bytes.Buffer
+	assert.NoError(t, decoded.Encode(&second))
+
+	// assert
+	assert.Equal(t, first.String(), second.String())
+}
+
+func TestDecodeNotRSS(t *testing.T) {
+	t.Parallel()
+
+	// act
+	p, err := podcast.Decode(strings.NewReader(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+
+	// assert
+	assert.Nil(t, p)
+	assert.Error(t, err)
+}
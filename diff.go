@@ -0,0 +1,305 @@
+package podcast
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/georgboe/rss-feed-generator/parser"
+	ext "github.com/georgboe/rss-feed-generator/parser/extensions"
+	"github.com/pkg/errors"
+)
+
+// ChangeKind is what happened to a field or Item between two feeds.
+type ChangeKind int
+
+// ChangeKinds of a Change.
+const (
+	// ChangeModified is a field whose value changed.
+	ChangeModified ChangeKind = iota
+	// ChangeAdded is an Item only in the regenerated feed.
+	ChangeAdded
+	// ChangeRemoved is an Item only in the live feed.
+	ChangeRemoved
+	// ChangeGUID is an Item whose GUID changed, which makes podcast apps
+	// download it again as a new episode.
+	ChangeGUID
+)
+
+// String returns the lower-case name of the ChangeKind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeModified:
+		return "modified"
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeGUID:
+		return "guid"
+	}
+	return "unknown"
+}
+
+// Change is a single difference reported by DiffFeeds.
+type Change struct {
+	Kind ChangeKind
+	// GUID identifies the Item in the regenerated feed, or in the live one when
+	// it was removed.  It is empty for the channel.
+	GUID string
+	// Field is the name of the field, such as "Title" or
+	// "Enclosure.Length".  It is empty for added and removed Items.
+	Field string
+	// Old and New are the values of the field.  For added and removed
+	// Items they are the title, and for ChangeGUID the GUIDs.
+	Old, New string
+}
+
+// String formats the Change as a line of a report.
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ item %s: %q", c.GUID, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("- item %s: %q", c.GUID, c.Old)
+	case ChangeGUID:
+		return fmt.Sprintf("! item guid changed: %q -> %q", c.Old, c.New)
+	}
+	if len(c.GUID) == 0 {
+		return fmt.Sprintf("~ %s: %q -> %q", c.Field, c.Old, c.New)
+	}
+	return fmt.Sprintf("~ item %s %s: %q -> %q", c.GUID, c.Field, c.Old, c.New)
+}
+
+// FeedDiff is the report of DiffFeeds: the channel changes first, then
+// the Items in the order of the regenerated feed, then the removed Items.
+type FeedDiff []Change
+
+// Empty reports whether the feeds are the same.
+func (d FeedDiff) Empty() bool {
+	return len(d) == 0
+}
+
+// GUIDChurn returns the Items whose GUID changed.
+func (d FeedDiff) GUIDChurn() FeedDiff {
+	var churn FeedDiff
+	for _, c := range d {
+		if c.Kind == ChangeGUID {
+			churn = append(churn, c)
+		}
+	}
+	return churn
+}
+
+// String formats the report, one Change per line.
+func (d FeedDiff) String() string {
+	lines := make([]string, len(d))
+	for n, c := range d {
+		lines[n] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffField reads a field of a feed or Item for comparison.
+type diffField struct {
+	name string
+	feed func(f *parser.Feed) string
+	item func(i *parser.Item) string
+}
+
+var channelDiffFields = []diffField{
+	{name: "Title", feed: func(f *parser.Feed) string { return f.Title }},
+	{name: "Description", feed: func(f *parser.Feed) string { return f.Description }},
+	{name: "Link", feed: func(f *parser.Feed) string { return f.Link }},
+	{name: "Language", feed: func(f *parser.Feed) string { return f.Language }},
+	{name: "Copyright", feed: func(f *parser.Feed) string { return f.Copyright }},
+	{name: "Categories", feed: func(f *parser.Feed) string { return strings.Join(f.Categories, ", ") }},
+	{name: "Image.URL", feed: func(f *parser.Feed) string {
+		if f.Image == nil {
+			return ""
+		}
+		return f.Image.URL
+	}},
+	{name: "ITunesExt.Author", feed: func(f *parser.Feed) string { return itunesFeed(f).Author }},
+	{name: "ITunesExt.Subtitle", feed: func(f *parser.Feed) string { return itunesFeed(f).Subtitle }},
+	{name: "ITunesExt.Summary", feed: func(f *parser.Feed) string { return itunesFeed(f).Summary }},
+	{name: "ITunesExt.Image", feed: func(f *parser.Feed) string { return itunesFeed(f).Image }},
+	{name: "ITunesExt.Explicit", feed: func(f *parser.Feed) string { return itunesFeed(f).Explicit }},
+	{name: "ITunesExt.Type", feed: func(f *parser.Feed) string { return itunesFeed(f).Type }},
+	{name: "ITunesExt.Block", feed: func(f *parser.Feed) string { return itunesFeed(f).Block }},
+	{name: "ITunesExt.Complete", feed: func(f *parser.Feed) string { return itunesFeed(f).Complete }},
+	{name: "ITunesExt.NewFeedURL", feed: func(f *parser.Feed) string { return itunesFeed(f).NewFeedURL }},
+	{name: "ITunesExt.Categories", feed: func(f *parser.Feed) string {
+		var cats []string
+		for _, c := range itunesFeed(f).Categories {
+			for ; c != nil; c = c.Subcategory {
+				cats = append(cats, c.Text)
+			}
+		}
+		return strings.Join(cats, ", ")
+	}},
+	{name: "ITunesExt.Owner", feed: func(f *parser.Feed) string {
+		if o := itunesFeed(f).Owner; o != nil {
+			return strings.TrimSpace(o.Name + " <" + o.Email + ">")
+		}
+		return ""
+	}},
+}
+
+var itemDiffFields = []diffField{
+	{name: "Title", item: func(i *parser.Item) string { return i.Title }},
+	{name: "Link", item: func(i *parser.Item) string { return i.Link }},
+	{name: "Description", item: func(i *parser.Item) string { return i.Description }},
+	{name: "Published", item: func(i *parser.Item) string { return i.Published }},
+	{name: "Enclosure.URL", item: func(i *parser.Item) string { return itemEnclosure(i).URL }},
+	{name: "Enclosure.Length", item: func(i *parser.Item) string { return itemEnclosure(i).Length }},
+	{name: "Enclosure.Type", item: func(i *parser.Item) string { return itemEnclosure(i).Type }},
+	{name: "ITunesExt.Duration", item: func(i *parser.Item) string { return itunesItem(i).Duration }},
+	{name: "ITunesExt.Episode", item: func(i *parser.Item) string { return itunesItem(i).Episode }},
+	{name: "ITunesExt.Season", item: func(i *parser.Item) string { return itunesItem(i).Season }},
+	{name: "ITunesExt.EpisodeType", item: func(i *parser.Item) string { return itunesItem(i).EpisodeType }},
+	{name: "ITunesExt.Explicit", item: func(i *parser.Item) string { return itunesItem(i).Explicit }},
+	{name: "ITunesExt.Image", item: func(i *parser.Item) string { return itunesItem(i).Image }},
+}
+
+// DiffFeeds compares the live feed from with the regenerated feed to.
+//
+// Items are matched by GUID, falling back to the enclosure URL and then
+// the link for Items without one.  An Item that only changed its GUID,
+// recognised by its enclosure URL or else its link and title, is
+// reported once as ChangeGUID rather than as removed and added.
+func DiffFeeds(from, to *parser.Feed) (FeedDiff, error) {
+	if from == nil || to == nil {
+		return nil, errors.New("podcast.DiffFeeds: feed is nil")
+	}
+
+	var diff FeedDiff
+	for _, f := range channelDiffFields {
+		if o, n := f.feed(from), f.feed(to); o != n {
+			diff = append(diff, Change{Kind: ChangeModified, Field: f.name, Old: o, New: n})
+		}
+	}
+
+	oldByKey := make(map[string]*parser.Item)
+	for _, i := range from.Items {
+		if k := itemKey(i); len(k) != 0 {
+			if _, ok := oldByKey[k]; !ok {
+				oldByKey[k] = i
+			}
+		}
+	}
+	matched := make(map[*parser.Item]bool)
+	var added []*parser.Item
+	for _, i := range to.Items {
+		if o, ok := oldByKey[itemKey(i)]; ok && !matched[o] {
+			matched[o] = true
+			diff = append(diff, diffItems(o, i)...)
+			continue
+		}
+		added = append(added, i)
+	}
+
+	var removed []*parser.Item
+	for _, i := range from.Items {
+		if !matched[i] {
+			removed = append(removed, i)
+		}
+	}
+
+	for _, i := range added {
+		if o := findRenamedItem(removed, matched, i); o != nil {
+			matched[o] = true
+			diff = append(diff, Change{Kind: ChangeGUID, GUID: itemKey(i), Old: itemKey(o), New: itemKey(i)})
+			diff = append(diff, diffItems(o, i)...)
+			continue
+		}
+		diff = append(diff, Change{Kind: ChangeAdded, GUID: itemKey(i), New: i.Title})
+	}
+	for _, i := range removed {
+		if !matched[i] {
+			diff = append(diff, Change{Kind: ChangeRemoved, GUID: itemKey(i), Old: i.Title})
+		}
+	}
+	return diff, nil
+}
+
+// Diff compares the Podcast with the live feed, see DiffFeeds.  The
+// Podcast is encoded and parsed back first, so that both sides are read
+// the same way.
+func (p *Podcast) Diff(live *parser.Feed) (FeedDiff, error) {
+	b := new(bytes.Buffer)
+	if err := p.Encode(b); err != nil {
+		return nil, errors.Wrap(err, "podcast.Diff: p.Encode returned error")
+	}
+	feed, err := parser.NewParser().Parse(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.Diff: parsing the encoded Podcast")
+	}
+	diff, err := DiffFeeds(live, feed)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.Diff")
+	}
+	return diff, nil
+}
+
+// diffItems compares the fields of two matched Items.
+func diffItems(from, to *parser.Item) FeedDiff {
+	var diff FeedDiff
+	for _, f := range itemDiffFields {
+		if o, n := f.item(from), f.item(to); o != n {
+			diff = append(diff, Change{Kind: ChangeModified, GUID: itemKey(to), Field: f.name, Old: o, New: n})
+		}
+	}
+	return diff
+}
+
+// findRenamedItem returns the unmatched removed Item that the added Item
+// replaces under a new GUID, if any.
+func findRenamedItem(removed []*parser.Item, matched map[*parser.Item]bool, i *parser.Item) *parser.Item {
+	url := itemEnclosure(i).URL
+	for _, o := range removed {
+		if matched[o] {
+			continue
+		}
+		if len(url) != 0 && itemEnclosure(o).URL == url {
+			return o
+		}
+		if len(url) == 0 && len(i.Link) != 0 && o.Link == i.Link && o.Title == i.Title {
+			return o
+		}
+	}
+	return nil
+}
+
+// itemKey identifies an Item as podcast apps do: by GUID, or else by
+// enclosure URL or link.
+func itemKey(i *parser.Item) string {
+	switch {
+	case len(i.GUID) != 0:
+		return i.GUID
+	case len(itemEnclosure(i).URL) != 0:
+		return itemEnclosure(i).URL
+	}
+	return i.Link
+}
+
+func itemEnclosure(i *parser.Item) *parser.Enclosure {
+	if len(i.Enclosures) == 0 || i.Enclosures[0] == nil {
+		return &parser.Enclosure{}
+	}
+	return i.Enclosures[0]
+}
+
+func itunesFeed(f *parser.Feed) *ext.ITunesFeedExtension {
+	if f.ITunesExt == nil {
+		return &ext.ITunesFeedExtension{}
+	}
+	return f.ITunesExt
+}
+
+func itunesItem(i *parser.Item) *ext.ITunesItemExtension {
+	if i.ITunesExt == nil {
+		return &ext.ITunesItemExtension{}
+	}
+	return i.ITunesExt
+}
@@ -0,0 +1,154 @@
+package podcast_test
+
+import (
+	"strings"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/georgboe/rss-feed-generator/parser"
+	ext "github.com/georgboe/rss-feed-generator/parser/extensions"
+	"github.com/stretchr/testify/assert"
+)
+
+func diffItem(t *testing.T, guid, title, url, length string) *parser.Item {
+	t.Helper()
+	return &parser.Item{
+		GUID:       guid,
+		Title:      title,
+		Enclosures: []*parser.Enclosure{{URL: url, Length: length, Type: "audio/mpeg"}},
+	}
+}
+
+func TestDiffFeeds(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	live := &parser.Feed{
+		Title:     "Show",
+		ITunesExt: &ext.ITunesFeedExtension{Author: "Jane"},
+		Items: []*parser.Item{
+			diffItem(t, "1", "One", "http://example.com/1.mp3", "100"),
+			diffItem(t, "2", "Two", "http://example.com/2.mp3", "200"),
+			diffItem(t, "3", "Three", "http://example.com/3.mp3", "300"),
+			diffItem(t, "4", "Four", "http://example.com/4.mp3", "400"),
+		},
+	}
+	regenerated := &parser.Feed{
+		Title: "The Show",
+		Items: []*parser.Item{
+			diffItem(t, "5", "Five", "http://example.com/5.mp3", "500"),
+			diffItem(t, "1", "One", "http://example.com/1.mp3", "100"),
+			diffItem(t, "2", "Two (remastered)", "http://example.com/2-v2.mp3", "250"),
+			diffItem(t, "urn:3", "Three", "http://example.com/3.mp3", "300"),
+		},
+	}
+
+	// act
+	diff, err := podcast.DiffFeeds(live, regenerated)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, podcast.FeedDiff{
+		{Kind: podcast.ChangeModified, Field: "Title", Old: "Show", New: "The Show"},
+		{Kind: podcast.ChangeModified, Field: "ITunesExt.Author", Old: "Jane", New: ""},
+		{Kind: podcast.ChangeModified, GUID: "2", Field: "Title", Old: "Two", New: "Two (remastered)"},
+		{Kind: podcast.ChangeModified, GUID: "2", Field: "Enclosure.URL", Old: "http://example.com/2.mp3", New: "http://example.com/2-v2.mp3"},
+		{Kind: podcast.ChangeModified, GUID: "2", Field: "Enclosure.Length", Old: "200", New: "250"},
+		{Kind: podcast.ChangeAdded, GUID: "5", New: "Five"},
+		{Kind: podcast.ChangeGUID, GUID: "urn:3", Old: "3", New: "urn:3"},
+		{Kind: podcast.ChangeRemoved, GUID: "4", Old: "Four"},
+	}, diff)
+	assert.Equal(t, podcast.FeedDiff{diff[6]}, diff.GUIDChurn())
+	assert.False(t, diff.Empty())
+}
+
+func TestDiffFeedsWithoutGUIDs(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	live := &parser.Feed{Items: []*parser.Item{
+		diffItem(t, "", "One", "http://example.com/1.mp3", "100"),
+		{Title: "Post", Link: "http://example.com/post"},
+	}}
+	regenerated := &parser.Feed{Items: []*parser.Item{
+		diffItem(t, "", "One", "http://example.com/1.mp3", "100"),
+		{Title: "Post", Link: "http://example.com/post", GUID: "post"},
+	}}
+
+	// act
+	diff, err := podcast.DiffFeeds(live, regenerated)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, podcast.FeedDiff{
+		{Kind: podcast.ChangeGUID, GUID: "post", Old: "http://example.com/post", New: "post"},
+	}, diff)
+}
+
+func TestDiffFeedsNil(t *testing.T) {
+	t.Parallel()
+
+	_, err := podcast.DiffFeeds(nil, &parser.Feed{})
+
+	assert.Error(t, err)
+}
+
+func TestFeedDiffString(t *testing.T) {
+	t.Parallel()
+
+	diff := podcast.FeedDiff{
+		{Kind: podcast.ChangeModified, Field: "Title", Old: "Show", New: "The Show"},
+		{Kind: podcast.ChangeModified, GUID: "2", Field: "Enclosure.Length", Old: "200", New: "250"},
+		{Kind: podcast.ChangeAdded, GUID: "5", New: "Five"},
+		{Kind: podcast.ChangeRemoved, GUID: "4", Old: "Four"},
+		{Kind: podcast.ChangeGUID, GUID: "urn:3", Old: "3", New: "urn:3"},
+	}
+
+	assert.Equal(t, `~ Title: "Show" -> "The Show"
+~ item 2 Enclosure.Length: "200" -> "250"
++ item 5: "Five"
+- item 4: "Four"
+! item guid changed: "3" -> "urn:3"`, diff.String())
+}
+
+func TestPodcastDiff(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("Show", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	i := podcast.Item{Title: "One", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "", 100)
+	if _, err := p.AddItem(i); err != nil {
+		t.Fatal(err)
+	}
+	live, err := parser.NewParser().Parse(strings.NewReader(p.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Items[0].Enclosure.Length = 120
+	p.Items[0].Enclosure.LengthFormatted = "120"
+
+	// act
+	diff, err := p.Diff(live)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, podcast.FeedDiff{
+		{Kind: podcast.ChangeModified, GUID: "http://example.com/1.mp3", Field: "Enclosure.Length", Old: "100", New: "120"},
+	}, diff)
+}
+
+func TestPodcastDiffUnchanged(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("Show", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	live, err := parser.NewParser().Parse(strings.NewReader(p.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := p.Diff(live)
+
+	assert.NoError(t, err)
+	assert.True(t, diff.Empty())
+}
@@ -0,0 +1,86 @@
+package podcast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DurationFormat is the style in which itunes:duration is written.
+type DurationFormat int
+
+// DurationFormat styles.  Apple accepts all of them, the seconds being
+// the recommended one.
+const (
+	// DurationAsIs leaves the itunes:duration of the Items as it is set.
+	// FormatDuration writes it as DurationSeconds.
+	DurationAsIs DurationFormat = iota
+	// DurationSeconds writes the total number of seconds, such as "3723".
+	DurationSeconds
+	// DurationHMS writes hours, minutes and seconds, such as "1:02:03".
+	DurationHMS
+	// DurationMMSS writes minutes and seconds, such as "62:03".
+	DurationMMSS
+)
+
+// FormatDuration formats d, rounded to the second, as an itunes:duration
+// in the format.
+func FormatDuration(d time.Duration, format DurationFormat) string {
+	seconds := int64(d.Round(time.Second) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	switch format {
+	case DurationHMS:
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	case DurationMMSS:
+		return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+	}
+	return strconv.FormatInt(seconds, 10)
+}
+
+// ParseDuration parses an itunes:duration written as seconds, MM:SS or
+// H:MM:SS, as found in parser/extensions.ITunesItemExtension.Duration.
+// The seconds may have a fraction, such as "1:02.5".
+func ParseDuration(duration string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(duration), ":")
+	if len(parts) > 3 {
+		return 0, errors.New("invalid duration " + duration)
+	}
+
+	var total time.Duration
+	last := len(parts) - 1
+	for n, part := range parts {
+		total *= 60
+		if n == last {
+			s, err := strconv.ParseFloat(part, 64)
+			if err != nil || s < 0 || strings.ContainsAny(part, "eEnN+-") {
+				return 0, errors.New("invalid duration " + duration)
+			}
+			total += time.Duration(s * float64(time.Second))
+			continue
+		}
+		m, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || m < 0 {
+			return 0, errors.New("invalid duration " + duration)
+		}
+		total += time.Duration(m) * time.Second
+	}
+	return total, nil
+}
+
+// formatDuration rewrites the itunes:duration of the copy of an Item
+// being encoded in the DurationFormat of the Podcast, leaving unparsable
+// values as they are.
+func (p *Podcast) formatDuration(i *Item) {
+	if p.DurationFormat == DurationAsIs || len(i.IDuration) == 0 {
+		return
+	}
+	if d, err := ParseDuration(i.IDuration); err == nil {
+		i.IDuration = FormatDuration(d, p.DurationFormat)
+	}
+}
@@ -0,0 +1,165 @@
+package podcast_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/georgboe/rss-feed-generator/parser/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		d      time.Duration
+		format podcast.DurationFormat
+		want   string
+	}{
+		{0, podcast.DurationSeconds, "0"},
+		{3723 * time.Second, podcast.DurationSeconds, "3723"},
+		{3723*time.Second + 600*time.Millisecond, podcast.DurationSeconds, "3724"},
+		{3723 * time.Second, podcast.DurationHMS, "1:02:03"},
+		{59 * time.Second, podcast.DurationHMS, "0:00:59"},
+		{36000 * time.Second, podcast.DurationHMS, "10:00:00"},
+		{3723 * time.Second, podcast.DurationMMSS, "62:03"},
+		{5 * time.Second, podcast.DurationMMSS, "00:05"},
+		{-time.Second, podcast.DurationMMSS, "00:00"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, podcast.FormatDuration(tt.d, tt.format), tt.d.String())
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"3723", 3723 * time.Second},
+		{"62:03", 3723 * time.Second},
+		{"1:02:03", 3723 * time.Second},
+		{" 01:02:03 ", 3723 * time.Second},
+		{"1:02.5", 62500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		d, err := podcast.ParseDuration(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, d, tt.in)
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{"", "1:2:3:4", "-5", "1:-2", "abc", "1e3", "NaN", "1.5:00"} {
+		_, err := podcast.ParseDuration(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestItemSetDuration(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	i := podcast.Item{}
+
+	// act
+	i.SetDuration(90*time.Minute + 400*time.Millisecond)
+
+	// assert
+	assert.Equal(t, "5400", i.IDuration)
+	d, err := i.Duration()
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+}
+
+func TestItemDurationInvalid(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{IDuration: "soon"}
+
+	_, err := i.Duration()
+
+	assert.Error(t, err)
+}
+
+func TestPodcastDurationFormat(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.DurationFormat = podcast.DurationHMS
+	first := podcast.Item{Title: "1", Link: "http://example.com/1"}
+	first.SetDuration(3723 * time.Second)
+	second := podcast.Item{Title: "2", Link: "http://example.com/2", IDuration: "not a duration"}
+	_, err1 := p.AddItem(first)
+	_, err2 := p.AddItem(second)
+	streamed := &podcast.Item{Title: "3", Link: "http://example.com/3", IDuration: "90"}
+
+	// act
+	hms := p.String()
+	p.SetDurationFormat(podcast.DurationMMSS)
+	var b bytes.Buffer
+	err := p.EncodeStream(&b, podcast.ItemsFromSlice([]*podcast.Item{streamed}))
+
+	// assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err)
+	assert.Contains(t, hms, "<itunes:duration>1:02:03</itunes:duration>")
+	assert.Contains(t, hms, "<itunes:duration>not a duration</itunes:duration>")
+	assert.Contains(t, b.String(), "<itunes:duration>62:03</itunes:duration>")
+	assert.Contains(t, b.String(), "<itunes:duration>01:30</itunes:duration>")
+	assert.Equal(t, "3723", p.Items[0].IDuration)
+	assert.Equal(t, "90", streamed.IDuration)
+}
+
+func TestPodcastDurationAsIs(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	i := podcast.Item{Title: "1", Link: "http://example.com/1", IDuration: "1:02:03"}
+	_, err := p.AddItem(i)
+
+	// act
+	out := p.String()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, podcast.DurationAsIs, p.DurationFormat)
+	assert.Contains(t, out, "<itunes:duration>1:02:03</itunes:duration>")
+}
+
+func TestDurationRoundTripsThroughParser(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []podcast.DurationFormat{podcast.DurationSeconds, podcast.DurationHMS, podcast.DurationMMSS} {
+		// arrange
+		p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+		p.SetDurationFormat(format)
+		i := podcast.Item{Title: "1", Link: "http://example.com/1"}
+		i.SetDuration(3723 * time.Second)
+		if _, err := p.AddItem(i); err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		if err := p.Encode(&b); err != nil {
+			t.Fatal(err)
+		}
+
+		// act
+		feed, err := (&rss.Parser{}).Parse(&b)
+
+		// assert
+		assert.NoError(t, err)
+		d, err := podcast.ParseDuration(feed.Items[0].ITunesExt.Duration)
+		assert.NoError(t, err)
+		assert.Equal(t, 3723*time.Second, d, p.Items[0].IDuration)
+	}
+}
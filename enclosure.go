@@ -2,9 +2,18 @@ package podcast
 
 import (
 	"encoding/xml"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
 )
 
 // EnclosureType specifies the type of the enclosure.
+//
+// The types below are registered by default.  Use RegisterEnclosureType
+// to add others.
 const (
 	M4A EnclosureType = iota
 	M4V
@@ -13,6 +22,19 @@ const (
 	MOV
 	PDF
 	EPUB
+	AAC
+	OPUS
+	OGG
+	FLAC
+	WAV
+	WEBM
+	HLS
+	JPEG
+	PNG
+
+	// UnknownEnclosureType is returned for MIME types and extensions that
+	// are not registered.
+	UnknownEnclosureType EnclosureType = -1
 )
 
 const (
@@ -22,44 +44,220 @@ const (
 // EnclosureType specifies the type of the enclosure.
 type EnclosureType int
 
-func (et EnclosureType) GetEnclosureType(enclosureType string) EnclosureType {
-	switch enclosureType {
-	case "audio/x-m4a":
-		return M4A
-	case "audio/mpeg":
-		return MP3
-	case "video/x-m4v":
-		return M4V
-	case "video/mp4":
-		return MP4
-	case "video/quicktime":
-		return MOV
+// enclosureRegistry maps the enclosure types to their MIME types and file
+// extensions in both directions.
+type enclosureRegistry struct {
+	sync.RWMutex
+	mimeTypes  map[EnclosureType]string
+	extensions map[EnclosureType][]string
+	byMIME     map[string]EnclosureType
+	byExt      map[string]EnclosureType
+	next       EnclosureType
+}
+
+var enclosureTypes = newEnclosureRegistry()
+
+func newEnclosureRegistry() *enclosureRegistry {
+	r := &enclosureRegistry{
+		mimeTypes:  map[EnclosureType]string{},
+		extensions: map[EnclosureType][]string{},
+		byMIME:     map[string]EnclosureType{},
+		byExt:      map[string]EnclosureType{},
+	}
+	// https://help.apple.com/itc/podcasts_connect/#/itcb54353390
+	for _, b := range []struct {
+		et         EnclosureType
+		mimeType   string
+		aliases    []string
+		extensions []string
+	}{
+		{M4A, "audio/x-m4a", []string{"audio/m4a", "audio/mp4"}, []string{".m4a"}},
+		{M4V, "video/x-m4v", nil, []string{".m4v"}},
+		{MP4, "video/mp4", nil, []string{".mp4"}},
+		{MP3, "audio/mpeg", []string{"audio/mp3", "audio/mpeg3"}, []string{".mp3"}},
+		{MOV, "video/quicktime", nil, []string{".mov"}},
+		{PDF, "application/pdf", nil, []string{".pdf"}},
+		{EPUB, "document/x-epub", []string{"application/epub+zip"}, []string{".epub"}},
+		{AAC, "audio/aac", []string{"audio/x-aac"}, []string{".aac"}},
+		{OPUS, "audio/opus", nil, []string{".opus"}},
+		{OGG, "audio/ogg", []string{"application/ogg"}, []string{".ogg", ".oga"}},
+		{FLAC, "audio/flac", []string{"audio/x-flac"}, []string{".flac"}},
+		{WAV, "audio/wav", []string{"audio/x-wav", "audio/wave"}, []string{".wav"}},
+		{WEBM, "video/webm", nil, []string{".webm"}},
+		{HLS, "application/x-mpegURL", []string{"application/vnd.apple.mpegurl", "audio/mpegurl"}, []string{".m3u8"}},
+		{JPEG, "image/jpeg", nil, []string{".jpg", ".jpeg"}},
+		{PNG, "image/png", nil, []string{".png"}},
+	} {
+		r.mimeTypes[b.et] = b.mimeType
+		r.extensions[b.et] = b.extensions
+		for _, mimeType := range append([]string{b.mimeType}, b.aliases...) {
+			r.byMIME[normalizeMIME(mimeType)] = b.et
+		}
+		for _, ext := range b.extensions {
+			r.byExt[ext] = b.et
+		}
+		if b.et >= r.next {
+			r.next = b.et + 1
+		}
+	}
+	return r
+}
+
+// RegisterEnclosureType adds a MIME type and its file extensions, such as
+// ".mka", to the known enclosure types and returns its EnclosureType.
+//
+// Registering a MIME type that is already known adds the extensions to
+// it.  An extension that belongs to another type is an error.
+func RegisterEnclosureType(mimeType string, extensions ...string) (EnclosureType, error) {
+	key := normalizeMIME(mimeType)
+	if len(key) == 0 || !strings.Contains(key, "/") {
+		return UnknownEnclosureType, errors.New("podcast.RegisterEnclosureType: invalid MIME type " + mimeType)
+	}
+
+	r := enclosureTypes
+	r.Lock()
+	defer r.Unlock()
+
+	et, ok := r.byMIME[key]
+	if !ok {
+		et = r.next
+	}
+	exts := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		ext = normalizeExtension(ext)
+		if len(ext) <= 1 {
+			return UnknownEnclosureType, errors.New("podcast.RegisterEnclosureType: invalid extension for " + mimeType)
+		}
+		if other, found := r.byExt[ext]; found && other != et {
+			return UnknownEnclosureType, errors.New("podcast.RegisterEnclosureType: extension " + ext + " is already registered to " + r.mimeTypes[other])
+		}
+		exts = append(exts, ext)
+	}
+
+	if !ok {
+		r.mimeTypes[et] = strings.TrimSpace(strings.Split(mimeType, ";")[0])
+		r.byMIME[key] = et
+		r.next++
+	}
+	for _, ext := range exts {
+		if _, found := r.byExt[ext]; !found {
+			r.byExt[ext] = et
+			r.extensions[et] = append(r.extensions[et], ext)
+		}
+	}
+	return et, nil
+}
+
+// EnclosureTypeByMIME returns the EnclosureType of a MIME type.  MIME
+// parameters, such as codecs, and letter case are ignored.
+func EnclosureTypeByMIME(mimeType string) (EnclosureType, error) {
+	r := enclosureTypes
+	r.RLock()
+	defer r.RUnlock()
+
+	if et, ok := r.byMIME[normalizeMIME(mimeType)]; ok {
+		return et, nil
+	}
+	return UnknownEnclosureType, errors.New("podcast.EnclosureTypeByMIME: unknown MIME type " + mimeType)
+}
+
+// EnclosureTypeByExtension returns the EnclosureType of a file extension,
+// with or without the leading dot.
+func EnclosureTypeByExtension(ext string) (EnclosureType, error) {
+	r := enclosureTypes
+	r.RLock()
+	defer r.RUnlock()
+
+	if et, ok := r.byExt[normalizeExtension(ext)]; ok {
+		return et, nil
 	}
-	return MP3
+	return UnknownEnclosureType, errors.New("podcast.EnclosureTypeByExtension: unknown extension " + ext)
+}
+
+// EnclosureTypeFromURL infers the EnclosureType from the file extension
+// of the path of an enclosure URL.  The query string is ignored.
+func EnclosureTypeFromURL(enclosureURL string) (EnclosureType, error) {
+	u, err := url.Parse(enclosureURL)
+	if err != nil {
+		return UnknownEnclosureType, errors.Wrap(err, "podcast.EnclosureTypeFromURL: url.Parse returned error")
+	}
+	ext := path.Ext(u.Path)
+	if len(ext) == 0 {
+		return UnknownEnclosureType, errors.New("podcast.EnclosureTypeFromURL: no extension in " + enclosureURL)
+	}
+	et, err := EnclosureTypeByExtension(ext)
+	if err != nil {
+		return UnknownEnclosureType, errors.Wrap(err, "podcast.EnclosureTypeFromURL")
+	}
+	return et, nil
+}
+
+// GetEnclosureType returns the EnclosureType of the MIME type, or
+// UnknownEnclosureType when it is not registered.
+//
+// Deprecated: use EnclosureTypeByMIME, which reports unknown types as an
+// error.
+func (et EnclosureType) GetEnclosureType(enclosureType string) EnclosureType {
+	t, _ := EnclosureTypeByMIME(enclosureType)
+	return t
 }
 
 // String returns the MIME type encoding of the specified EnclosureType.
 func (et EnclosureType) String() string {
-	// https://help.apple.com/itc/podcasts_connect/#/itcb54353390
-	switch et {
-	case M4A:
-		return "audio/x-m4a"
-	case M4V:
-		return "video/x-m4v"
-	case MP4:
-		return "video/mp4"
-	case MP3:
-		return "audio/mpeg"
-	case MOV:
-		return "video/quicktime"
-	case PDF:
-		return "application/pdf"
-	case EPUB:
-		return "document/x-epub"
+	r := enclosureTypes
+	r.RLock()
+	defer r.RUnlock()
+
+	if mimeType, ok := r.mimeTypes[et]; ok {
+		return mimeType
 	}
 	return enclosureDefault
 }
 
+// Extension returns the preferred file extension of the EnclosureType,
+// such as ".mp3", or an empty string when it has none.
+func (et EnclosureType) Extension() string {
+	exts := et.Extensions()
+	if len(exts) == 0 {
+		return ""
+	}
+	return exts[0]
+}
+
+// Extensions returns all file extensions of the EnclosureType, preferred
+// first.
+func (et EnclosureType) Extensions() []string {
+	r := enclosureTypes
+	r.RLock()
+	defer r.RUnlock()
+
+	return append([]string{}, r.extensions[et]...)
+}
+
+// IsRegistered reports whether the EnclosureType is known.
+func (et EnclosureType) IsRegistered() bool {
+	r := enclosureTypes
+	r.RLock()
+	defer r.RUnlock()
+
+	_, ok := r.mimeTypes[et]
+	return ok
+}
+
+// normalizeMIME drops the MIME parameters and letter case.
+func normalizeMIME(mimeType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+}
+
+// normalizeExtension lowers the extension and adds the leading dot.
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
 // Enclosure represents a download enclosure.
 type Enclosure struct {
 	XMLName xml.Name `xml:"enclosure"`
@@ -21,7 +21,17 @@ var enclosureTests = []enclosureTest{
 	{podcast.PDF, "application/pdf"},
 	{podcast.EPUB, "document/x-epub"},
 	{podcast.M4A, "audio/x-m4a"},
+	{podcast.AAC, "audio/aac"},
+	{podcast.OPUS, "audio/opus"},
+	{podcast.OGG, "audio/ogg"},
+	{podcast.FLAC, "audio/flac"},
+	{podcast.WAV, "audio/wav"},
+	{podcast.WEBM, "video/webm"},
+	{podcast.HLS, "application/x-mpegURL"},
+	{podcast.JPEG, "image/jpeg"},
+	{podcast.PNG, "image/png"},
 	{99, "application/octet-stream"},
+	{podcast.UnknownEnclosureType, "application/octet-stream"},
 }
 
 func TestEnclosureTypes(t *testing.T) {
@@ -35,3 +45,123 @@ func TestEnclosureTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestEnclosureTypeByMIME(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mimeType string
+		expected podcast.EnclosureType
+	}{
+		{"audio/mpeg", podcast.MP3},
+		{"audio/mp3", podcast.MP3},
+		{"audio/mp4", podcast.M4A},
+		{"Audio/Ogg; codecs=opus", podcast.OGG},
+		{"application/vnd.apple.mpegurl", podcast.HLS},
+		{"audio/x-flac", podcast.FLAC},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.mimeType, func(t *testing.T) {
+			t.Parallel()
+
+			et, err := podcast.EnclosureTypeByMIME(tt.mimeType)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, et)
+		})
+	}
+}
+
+func TestEnclosureTypeByMIMEUnknown(t *testing.T) {
+	t.Parallel()
+
+	et, err := podcast.EnclosureTypeByMIME("audio/x-unknown")
+
+	assert.Error(t, err)
+	assert.Equal(t, podcast.UnknownEnclosureType, et)
+	assert.False(t, et.IsRegistered())
+}
+
+func TestGetEnclosureTypeUnknown(t *testing.T) {
+	t.Parallel()
+
+	var et podcast.EnclosureType
+
+	assert.Equal(t, podcast.FLAC, et.GetEnclosureType("audio/flac"))
+	assert.Equal(t, podcast.UnknownEnclosureType, et.GetEnclosureType("audio/x-unknown"))
+}
+
+func TestEnclosureTypeByExtension(t *testing.T) {
+	t.Parallel()
+
+	et, err := podcast.EnclosureTypeByExtension("JPEG")
+	assert.NoError(t, err)
+	assert.Equal(t, podcast.JPEG, et)
+	assert.Equal(t, ".jpg", et.Extension())
+	assert.Equal(t, []string{".jpg", ".jpeg"}, et.Extensions())
+
+	_, err = podcast.EnclosureTypeByExtension(".xyz")
+	assert.Error(t, err)
+}
+
+func TestEnclosureTypeFromURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url      string
+		expected podcast.EnclosureType
+		err      bool
+	}{
+		{"http://example.com/1.opus", podcast.OPUS, false},
+		{"http://example.com/show/1.M4A?token=abc.mp3", podcast.M4A, false},
+		{"https://cdn.example.com/hls/1/master.m3u8", podcast.HLS, false},
+		{"http://example.com/1", podcast.UnknownEnclosureType, true},
+		{"http://example.com/1.xyz", podcast.UnknownEnclosureType, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+
+			et, err := podcast.EnclosureTypeFromURL(tt.url)
+
+			assert.Equal(t, tt.err, err != nil)
+			assert.Equal(t, tt.expected, et)
+		})
+	}
+}
+
+func TestRegisterEnclosureType(t *testing.T) {
+	t.Parallel()
+
+	// act
+	et, err := podcast.RegisterEnclosureType("audio/x-matroska", ".mka", "MKA2")
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, et.IsRegistered())
+	assert.Equal(t, "audio/x-matroska", et.String())
+	assert.Equal(t, []string{".mka", ".mka2"}, et.Extensions())
+	byURL, err := podcast.EnclosureTypeFromURL("http://example.com/1.mka")
+	assert.NoError(t, err)
+	assert.Equal(t, et, byURL)
+	again, err := podcast.RegisterEnclosureType("audio/x-matroska; codecs=opus", ".mka3")
+	assert.NoError(t, err)
+	assert.Equal(t, et, again)
+	assert.Equal(t, []string{".mka", ".mka2", ".mka3"}, et.Extensions())
+}
+
+func TestRegisterEnclosureTypeInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := podcast.RegisterEnclosureType("audio/x-other-mp3", ".mp3")
+	assert.Error(t, err)
+	_, err = podcast.RegisterEnclosureType("mpeg")
+	assert.Error(t, err)
+	_, err = podcast.RegisterEnclosureType("audio/x-empty", "")
+	assert.Error(t, err)
+
+	_, err = podcast.EnclosureTypeByMIME("audio/x-other-mp3")
+	assert.Error(t, err)
+}
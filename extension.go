@@ -0,0 +1,132 @@
+package podcast
+
+import (
+	"encoding/xml"
+	"sort"
+	"sync"
+
+	ext "github.com/georgboe/rss-feed-generator/parser/extensions"
+	"github.com/pkg/errors"
+)
+
+// ExtensionElement is an element of a namespace that has no Podcast or
+// Item field, kept as parsed so that it is written back out as-is.
+//
+// The element declares its namespace itself, unless the rss element
+// already does, so its Prefix must be registered with
+// RegisterExtensionNamespace.  The children are written in the same
+// namespace, in order of their names.
+type ExtensionElement struct {
+	Prefix    string
+	Extension ext.Extension
+}
+
+// rootNamespaces are the prefixes declared on the rss element by Encode.
+var rootNamespaces = map[string]bool{
+	"atom":       true,
+	"podcast":    true,
+	"itunes":     true,
+	"content":    true,
+	"googleplay": true,
+}
+
+var extensionNamespaces = struct {
+	sync.RWMutex
+	uris map[string]string
+}{uris: map[string]string{
+	"atom":            ATOMNS,
+	"podcast":         PODCASTNS,
+	"itunes":          ITUNESNS,
+	"content":         CONTENT,
+	"googleplay":      GOOGLEPLAYNS,
+	"fh":              FHNS,
+	"psc":             PSCNS,
+	"media":           "http://search.yahoo.com/mrss/",
+	"dc":              "http://purl.org/dc/elements/1.1/",
+	"dcterms":         "http://purl.org/dc/terms/",
+	"sy":              "http://purl.org/rss/1.0/modules/syndication/",
+	"slash":           "http://purl.org/rss/1.0/modules/slash/",
+	"wfw":             "http://wellformedweb.org/commentAPI/",
+	"georss":          "http://www.georss.org/georss",
+	"geo":             "http://www.w3.org/2003/01/geo/wgs84_pos#",
+	"feedburner":      "http://rssnamespace.org/feedburner/ext/1.0",
+	"creativeCommons": "http://backend.userland.com/creativeCommonsRssModule",
+	"spotify":         "http://www.spotify.com/ns/rss",
+	"rawvoice":        "http://www.rawvoice.com/rawvoiceRssModule/",
+}}
+
+// RegisterExtensionNamespace sets the namespace URI of the prefix, so
+// that ExtensionElements of that prefix can be kept and written.
+func RegisterExtensionNamespace(prefix, uri string) error {
+	if len(prefix) == 0 || len(uri) == 0 {
+		return errors.New("podcast.RegisterExtensionNamespace: prefix and uri are required")
+	}
+	extensionNamespaces.Lock()
+	defer extensionNamespaces.Unlock()
+	extensionNamespaces.uris[prefix] = uri
+	return nil
+}
+
+// extensionNamespace returns the namespace URI of the prefix, if known.
+func extensionNamespace(prefix string) (string, bool) {
+	extensionNamespaces.RLock()
+	defer extensionNamespaces.RUnlock()
+	uri, ok := extensionNamespaces.uris[prefix]
+	return uri, ok
+}
+
+// MarshalXML writes the element, its attributes and its children.
+func (e ExtensionElement) MarshalXML(enc *xml.Encoder, _ xml.StartElement) error {
+	var attrs []xml.Attr
+	if !rootNamespaces[e.Prefix] {
+		uri, ok := extensionNamespace(e.Prefix)
+		if !ok {
+			return errors.New("podcast.ExtensionElement: unknown namespace prefix " + e.Prefix)
+		}
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + e.Prefix}, Value: uri})
+	}
+	return encodeExtension(enc, e.Prefix, e.Extension, attrs)
+}
+
+func encodeExtension(enc *xml.Encoder, prefix string, e ext.Extension, attrs []xml.Attr) error {
+	start := xml.StartElement{Name: xml.Name{Local: prefix + ":" + e.Name}, Attr: attrs}
+	// the parser keeps an xmlns:prefix declaration on the element itself
+	// as a plain prefix attribute; it is declared above instead.
+	uri, _ := extensionNamespace(prefix)
+	names := make([]string, 0, len(e.Attrs))
+	for name, value := range e.Attrs {
+		if name == prefix && value == uri {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: e.Attrs[name]})
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if len(e.Value) != 0 {
+		if err := enc.EncodeToken(xml.CharData(e.Value)); err != nil {
+			return err
+		}
+	}
+	names = names[:0]
+	for name := range e.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, child := range e.Children[name] {
+			if len(child.Name) == 0 {
+				child.Name = name
+			}
+			if err := encodeExtension(enc, prefix, child, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
@@ -15,3 +15,13 @@ type GooglePlayCategory struct {
 	Text                 string   `xml:"text,attr"`
 	GooglePlayCategories []*GooglePlayCategory
 }
+
+// googlePlayCategory converts an iTunes category tree into its
+// googleplay:category equivalent.
+func googlePlayCategory(icat *ICategory) *GooglePlayCategory {
+	gcat := &GooglePlayCategory{Text: icat.Text}
+	for _, sub := range icat.ICategories {
+		gcat.GooglePlayCategories = append(gcat.GooglePlayCategories, googlePlayCategory(sub))
+	}
+	return gcat
+}
@@ -0,0 +1,108 @@
+package podcast_test
+
+import (
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddGooglePlayFallsBackToITunes(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddAuthor([]string{"Jane"})
+	p.AddOwner("Jane", "jane@example.com")
+	p.AddImage("http://example.com/i.jpg")
+	p.AddCategory("Arts", []string{"Books"})
+	p.AddParentalAdvisory(podcast.ParentalAdvisoryClean)
+	p.AddItunesBlock("hide")
+	p.AddSummary("summary")
+
+	// act
+	p.AddGooglePlayAuthor("")
+	p.AddGooglePlayOwner("")
+	p.AddGooglePlayImage("")
+	p.AddGooglePlayCategory("", nil)
+	p.AddGooglePlayExplicit("")
+	p.AddGooglePlayBlock("")
+	p.AddGooglePlayDescription("")
+
+	// assert
+	assert.Equal(t, "Jane", p.GooglePlayAuthor)
+	assert.Equal(t, "jane@example.com", p.GooglePlayOwner)
+	assert.Equal(t, "http://example.com/i.jpg", p.GooglePlayImage.HREF)
+	assert.Len(t, p.GooglePlayCategories, 1)
+	assert.Equal(t, "Books", p.GooglePlayCategories[0].GooglePlayCategories[0].Text)
+	assert.Equal(t, "no", p.GooglePlayExplicit)
+	assert.Equal(t, "Yes", p.GooglePlayBlock)
+	assert.Equal(t, "summary", p.GooglePlayDescription)
+}
+
+func TestAddGooglePlayOverrides(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddAuthor([]string{"Jane"})
+	p.AddParentalAdvisory(podcast.ParentalAdvisoryClean)
+
+	// act
+	p.AddGooglePlayAuthor("John")
+	p.AddGooglePlayExplicit(podcast.ParentalAdvisoryExplicit)
+	p.AddGooglePlayCategory("Arts", []string{"", "Design"})
+	p.AddGooglePlayBlock("")
+
+	// assert
+	assert.Equal(t, "John", p.GooglePlayAuthor)
+	assert.Equal(t, "yes", p.GooglePlayExplicit)
+	assert.Len(t, p.GooglePlayCategories[0].GooglePlayCategories, 1)
+	assert.Equal(t, "No", p.GooglePlayBlock)
+}
+
+func TestAddGooglePlayImageEmpty(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	p.AddGooglePlayImage("")
+
+	assert.Nil(t, p.GooglePlayImage)
+}
+
+func TestItemAddGooglePlayFallsBackToITunes(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	i := podcast.Item{IAuthor: "Jane"}
+	i.AddImage("http://example.com/1.jpg")
+	i.AddParentalAdvisory(podcast.ParentalAdvisoryExplicit)
+	i.AddDescription(podcast.Description{Text: "<p>Show notes</p>"})
+
+	// act
+	i.AddGooglePlayAuthor("")
+	i.AddGooglePlayImage("")
+	i.AddGooglePlayExplicit("")
+	i.AddGooglePlayBlock("")
+	i.AddGooglePlayDescription("")
+
+	// assert
+	assert.Equal(t, "Jane", i.GooglePlayAuthor)
+	assert.Equal(t, "http://example.com/1.jpg", i.GooglePlayImage.HREF)
+	assert.Equal(t, "yes", i.GooglePlayExplicit)
+	assert.Equal(t, "No", i.GooglePlayBlock)
+	assert.Equal(t, "Show notes", i.GooglePlayDescription)
+}
+
+func TestEncodeGooglePlayNamespace(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddGooglePlayOwner("jane@example.com")
+
+	out := p.String()
+
+	assert.Contains(t, out, `xmlns:googleplay="http://www.google.com/schemas/play-podcasts/1.0"`)
+	assert.Contains(t, out, `<googleplay:owner>jane@example.com</googleplay:owner>`)
+}
@@ -5,6 +5,11 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
 )
 
 const (
@@ -12,13 +17,91 @@ const (
 	UNIX_LBR = "\n"
 )
 
-var lbr = WIN_LBR
-var badTagnamesRE = regexp.MustCompile(`^(head|script|style|a)($|\s+)`)
-var linkTagRE = regexp.MustCompile(`a.*href=('([^']*?)'|"([^"]*?)")`)
-var badLinkHrefRE = regexp.MustCompile(`javascript:`)
-var headersRE = regexp.MustCompile(`^(\/)?h[1-6]`)
+// LinkMode is how HTML2TextWithOptions writes links.
+type LinkMode int
+
+// LinkModes.
+const (
+	// LinksURL writes the URL of a link in place of its text, as
+	// HTML2Text always has.
+	LinksURL LinkMode = iota
+	// LinksInline writes the text of a link followed by its URL in
+	// parentheses.
+	LinksInline
+	// LinksFootnotes writes the text of a link followed by a [n]
+	// reference, and the numbered URLs at the end of the text.
+	LinksFootnotes
+	// LinksText writes the text of a link only.
+	LinksText
+)
+
+// TableMode is how HTML2TextWithOptions writes tables.
+type TableMode int
+
+// TableModes.
+const (
+	// TablesInline writes the cells of a table separated by spaces.
+	TablesInline TableMode = iota
+	// TablesRows writes every row on its own line, with the cells
+	// separated by " | ".
+	TablesRows
+	// TablesNone leaves tables out.
+	TablesNone
+)
+
+// Options are the settings of HTML2TextWithOptions.  The zero value
+// converts like HTML2Text with Windows line breaks.
+type Options struct {
+	// LineBreak ends the lines, WIN_LBR when empty.
+	LineBreak string
+
+	// Width wraps the lines at spaces so that they hold at most Width
+	// characters, unless a single word is longer.  Zero does not wrap.
+	Width int
+
+	Links  LinkMode
+	Tables TableMode
+
+	// Bullet starts the items of unordered lists, such as "* ", and of
+	// ordered lists unless NumberLists is set.  Nested items are
+	// indented by two spaces per level.
+	Bullet string
+	// NumberLists starts the items of ordered lists with "1. ", "2. "
+	// and so on.
+	NumberLists bool
+
+	// ImageAlt writes the alt text of images.
+	ImageAlt bool
+}
+
+var lbr atomic.Value
+
 var numericEntityRE = regexp.MustCompile(`(?i)^#(x?[a-f0-9]+)$`)
 
+// skipped are the elements left out with their content.
+var skipped = map[string]bool{
+	"head": true, "script": true, "style": true, "title": true,
+	"noscript": true, "template": true, "iframe": true, "object": true,
+	"svg": true, "math": true, "select": true, "textarea": true,
+}
+
+// lineBlocks are the elements written on lines of their own.
+var lineBlocks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true,
+	"figure": true, "footer": true, "header": true, "hr": true,
+	"nav": true, "pre": true, "section": true,
+}
+
+// paragraphBlocks are the elements separated by an empty line.
+var paragraphBlocks = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+func init() {
+	lbr.Store(WIN_LBR)
+}
+
 func parseHTMLEntity(entName string) (string, bool) {
 	if r, ok := entity[entName]; ok {
 		return string(r), true
@@ -47,11 +130,14 @@ func parseHTMLEntity(entName string) (string, bool) {
 
 // SetUnixLbr with argument true sets Unix-style line-breaks in output ("\n")
 // with argument false sets Windows-style line-breaks in output ("\r\n", the default)
+//
+// Deprecated: the setting is shared by every caller of HTML2Text.  Set
+// Options.LineBreak for HTML2TextWithOptions instead.
 func SetUnixLbr(b bool) {
 	if b {
-		lbr = UNIX_LBR
+		lbr.Store(UNIX_LBR)
 	} else {
-		lbr = WIN_LBR
+		lbr.Store(WIN_LBR)
 	}
 }
 
@@ -104,131 +190,318 @@ func HTMLEntitiesToText(htmlEntsText string) string {
 	return outBuf.String()
 }
 
-func writeSpace(outBuf *bytes.Buffer) {
-	bts := outBuf.Bytes()
-	if len(bts) > 0 && bts[len(bts)-1] != ' ' {
-		outBuf.WriteString(" ")
-	}
+// HTML2Text converts html into a text form, with the line breaks set by
+// SetUnixLbr.
+func HTML2Text(html string) string {
+	return HTML2TextWithOptions(html, Options{LineBreak: lbr.Load().(string)})
 }
 
-// HTML2Text converts html into a text form
-func HTML2Text(html string) string {
-	inLen := len(html)
-	tagStart := 0
-	inEnt := false
-	badTagStackDepth := 0 // if == 1 it means we are inside <head>...</head>
-	shouldOutput := true
-	// new line cannot be printed at the beginning or
-	// for <p> after a new line created by previous <p></p>
-	canPrintNewline := false
+// HTML2TextWithOptions converts html into a text form.
+//
+// Whitespace is collapsed as a browser would, paragraphs and headings
+// are separated by an empty line, and list items, line breaks and other
+// blocks start a new line.  Scripts, styles and the head are left out.
+func HTML2TextWithOptions(html string, opts Options) string {
+	c := &converter{opts: opts}
+	c.convert(html)
+	return c.String()
+}
 
-	outBuf := bytes.NewBufferString("")
+// list is an open ul or ol element.
+type list struct {
+	ordered bool
+	items   int
+}
 
-	for i, r := range html {
-		if inLen > 0 && i == inLen-1 {
-			// prevent new line at the end of the document
-			canPrintNewline = false
-		}
+// converter writes the text while html is tokenized.  Line breaks are
+// held in breaks until the next text, so that none are written at the
+// start or the end.
+type converter struct {
+	opts Options
+	b    bytes.Buffer
 
-		switch {
-		// skip new lines and spaces adding a single space if not there yet
-		case r <= 0xD, r == 0x85, r == 0x2028, r == 0x2029, // new lines
-			r == ' ', r >= 0x2008 && r <= 0x200B: // spaces
-			if shouldOutput && badTagStackDepth == 0 && !inEnt {
-				//outBuf.WriteString(fmt.Sprintf("{DBG r:%c, inEnt:%t, tag:%s}", r, inEnt, html[tagStart:i]))
-				writeSpace(outBuf)
-			}
-			continue
+	breaks int
+	space  bool
+	skip   []string
 
-		case r == ';' && inEnt: // end of html entity
-			inEnt = false
-			continue
+	href      string
+	linkStart int
+	inLink    bool
+	footnotes []string
 
-		case r == '&' && shouldOutput: // possible html entity
-			entName := ""
-			isEnt := false
+	lists []list
+	cells int
+}
 
-			// parse the entity name - max 10 chars
-			chars := 0
-			for _, er := range html[i+1:] {
-				if er == ';' {
-					isEnt = true
-					break
-				} else {
-					entName += string(er)
-				}
+func (c *converter) convert(s string) {
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return
+		}
+		t := z.Token()
+
+		if len(c.skip) != 0 {
+			switch {
+			case tt == html.StartTagToken && t.Data == c.skip[len(c.skip)-1]:
+				c.skip = append(c.skip, t.Data)
+			case tt == html.EndTagToken && t.Data == c.skip[len(c.skip)-1]:
+				c.skip = c.skip[:len(c.skip)-1]
+			}
+			continue
+		}
 
-				chars++
-				if chars == 10 {
-					break
-				}
+		switch tt {
+		case html.TextToken:
+			if !c.inLink || c.opts.Links != LinksURL {
+				c.text(t.Data)
 			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if (skipped[t.Data] || t.Data == "table" && c.opts.Tables == TablesNone) && tt == html.StartTagToken {
+				c.skip = append(c.skip, t.Data)
+				continue
+			}
+			c.start(t)
+		case html.EndTagToken:
+			c.end(t)
+		}
+	}
+}
 
-			if isEnt {
-				if ent, isEnt := parseHTMLEntity(entName); isEnt {
-					outBuf.WriteString(ent)
-					inEnt = true
-					continue
-				}
+func (c *converter) start(t html.Token) {
+	switch {
+	case paragraphBlocks[t.Data]:
+		c.lineBreak(2)
+	case lineBlocks[t.Data]:
+		c.lineBreak(1)
+	}
+
+	switch t.Data {
+	case "br":
+		c.breaks++
+		c.space = false
+	case "a":
+		c.startLink(attr(t, "href"))
+	case "img":
+		if c.opts.ImageAlt {
+			c.text(attr(t, "alt"))
+		}
+	case "ul", "ol":
+		c.lineBreak(1)
+		c.lists = append(c.lists, list{ordered: t.Data == "ol"})
+	case "li":
+		c.lineBreak(1)
+		c.listItem()
+	case "tr":
+		c.cells = 0
+		if c.opts.Tables == TablesRows {
+			c.lineBreak(1)
+		}
+	case "td", "th":
+		if c.cells != 0 {
+			if c.opts.Tables == TablesRows {
+				c.write(" | ")
+			} else {
+				c.space = true
 			}
+		}
+		c.cells++
+	}
+}
+
+func (c *converter) end(t html.Token) {
+	switch {
+	case paragraphBlocks[t.Data]:
+		c.lineBreak(2)
+	case lineBlocks[t.Data]:
+		c.lineBreak(1)
+	}
+
+	switch t.Data {
+	case "a":
+		c.endLink()
+	case "ul", "ol":
+		if len(c.lists) != 0 {
+			c.lists = c.lists[:len(c.lists)-1]
+		}
+		c.lineBreak(1)
+	case "li":
+		c.lineBreak(1)
+	case "tr":
+		if c.opts.Tables == TablesRows {
+			c.lineBreak(1)
+		} else {
+			c.space = true
+		}
+	case "table":
+		c.lineBreak(1)
+	}
+}
+
+// lineBreak asks for at least n line breaks before the next text.
+func (c *converter) lineBreak(n int) {
+	if c.breaks < n {
+		c.breaks = n
+	}
+	c.space = false
+}
 
-		case r == '<': // start of a tag
-			tagStart = i + 1
-			shouldOutput = false
+// text writes the text with its whitespace collapsed.
+func (c *converter) text(s string) {
+	for _, r := range s {
+		if isSpace(r) {
+			c.space = true
 			continue
+		}
+		c.flush()
+		c.b.WriteRune(r)
+	}
+}
 
-		case r == '>': // end of a tag
-			shouldOutput = true
-			tag := html[tagStart:i]
-			tagNameLowercase := strings.ToLower(tag)
-
-			if tagNameLowercase == "/ul" {
-				outBuf.WriteString(lbr)
-			} else if tagNameLowercase == "li" || tagNameLowercase == "li/" {
-				outBuf.WriteString(lbr)
-			} else if headersRE.MatchString(tagNameLowercase) {
-				if canPrintNewline {
-					outBuf.WriteString(lbr + lbr)
-				}
-				canPrintNewline = false
-			} else if tagNameLowercase == "br" || tagNameLowercase == "br/" {
-				// new line
-				outBuf.WriteString(lbr)
-			} else if tagNameLowercase == "p" || tagNameLowercase == "/p" {
-				if canPrintNewline {
-					outBuf.WriteString(lbr + lbr)
-				}
-				canPrintNewline = false
-			} else if badTagnamesRE.MatchString(tagNameLowercase) {
-				// unwanted block
-				badTagStackDepth++
-
-				// parse link href
-				m := linkTagRE.FindStringSubmatch(tag)
-				if len(m) == 4 {
-					link := m[2]
-					if len(link) == 0 {
-						link = m[3]
-					}
-
-					if !badLinkHrefRE.MatchString(link) {
-						outBuf.WriteString(HTMLEntitiesToText(link))
-					}
-				}
-			} else if len(tagNameLowercase) > 0 && tagNameLowercase[0] == '/' &&
-				badTagnamesRE.MatchString(tagNameLowercase[1:]) {
-				// end of unwanted block
-				badTagStackDepth--
+// write writes s as-is after the pending breaks.
+func (c *converter) write(s string) {
+	c.flush()
+	c.b.WriteString(s)
+}
+
+// flush writes the pending line breaks, or space, when there is text
+// before them.
+func (c *converter) flush() {
+	if c.b.Len() == 0 {
+		c.breaks, c.space = 0, false
+		return
+	}
+	switch {
+	case c.breaks != 0:
+		c.b.WriteString(strings.Repeat("\n", c.breaks))
+	case c.space:
+		c.b.WriteByte(' ')
+	}
+	c.breaks, c.space = 0, false
+}
+
+func (c *converter) listItem() {
+	if len(c.lists) == 0 {
+		return
+	}
+	l := &c.lists[len(c.lists)-1]
+	l.items++
+	marker := c.opts.Bullet
+	if l.ordered && c.opts.NumberLists {
+		marker = strconv.Itoa(l.items) + ". "
+	}
+	if len(marker) != 0 {
+		c.write(strings.Repeat("  ", len(c.lists)-1) + marker)
+	}
+}
+
+func (c *converter) startLink(href string) {
+	c.inLink = true
+	c.href = ""
+	if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(href)), "javascript:") {
+		c.href = href
+	}
+	if c.opts.Links == LinksURL {
+		c.text(c.href)
+	}
+	c.linkStart = c.b.Len()
+}
+
+func (c *converter) endLink() {
+	if !c.inLink {
+		return
+	}
+	c.inLink = false
+	if len(c.href) == 0 {
+		return
+	}
+	switch c.opts.Links {
+	case LinksInline:
+		if strings.TrimSpace(string(c.b.Bytes()[c.linkStart:])) != c.href {
+			c.text(" ")
+			c.write("(" + c.href + ")")
+		}
+	case LinksFootnotes:
+		n := 0
+		for k, f := range c.footnotes {
+			if f == c.href {
+				n = k + 1
 			}
-			continue
+		}
+		if n == 0 {
+			c.footnotes = append(c.footnotes, c.href)
+			n = len(c.footnotes)
+		}
+		c.write("[" + strconv.Itoa(n) + "]")
+	}
+}
 
-		} // switch end
+// String returns the text with the footnotes, wrapped and with the line
+// breaks of the Options.
+func (c *converter) String() string {
+	// trailing whitespace has always been kept as a single space
+	if c.space && c.breaks == 0 && len(c.footnotes) == 0 {
+		c.write("")
+	}
+	for n, f := range c.footnotes {
+		c.breaks, c.space = 1, false
+		if n == 0 {
+			c.breaks = 2
+		}
+		c.write("[" + strconv.Itoa(n+1) + "] " + f)
+	}
 
-		if shouldOutput && badTagStackDepth == 0 && !inEnt {
-			canPrintNewline = true
-			outBuf.WriteRune(r)
+	lines := strings.Split(c.b.String(), "\n")
+	if c.opts.Width > 0 {
+		var wrapped []string
+		for _, line := range lines {
+			wrapped = append(wrapped, wrap(line, c.opts.Width)...)
 		}
+		lines = wrapped
 	}
+	lineBreak := c.opts.LineBreak
+	if len(lineBreak) == 0 {
+		lineBreak = WIN_LBR
+	}
+	return strings.Join(lines, lineBreak)
+}
 
-	return outBuf.String()
+// wrap splits the line at spaces into lines of at most width characters.
+func wrap(line string, width int) []string {
+	if utf8.RuneCountInString(line) <= width {
+		return []string{line}
+	}
+	var (
+		lines   []string
+		current string
+	)
+	for _, word := range strings.Split(line, " ") {
+		switch {
+		case len(current) == 0:
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	return append(lines, current)
+}
+
+func attr(t html.Token, key string) string {
+	for _, a := range t.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// isSpace reports whether r is collapsed into a single space, as the
+// line breaks and spaces HTML2Text has always collapsed.
+func isSpace(r rune) bool {
+	return r <= 0xD || r == 0x85 || r == 0x2028 || r == 0x2029 ||
+		r == ' ' || r >= 0x2008 && r <= 0x200B || r != 0xA0 && unicode.IsSpace(r)
 }
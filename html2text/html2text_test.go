@@ -0,0 +1,109 @@
+package html2text_test
+
+import (
+	"testing"
+
+	"github.com/georgboe/rss-feed-generator/html2text"
+	"github.com/stretchr/testify/assert"
+)
+
+const showNotes = `<h1>Show  notes</h1>` +
+	`<p>Hello &amp; <a href="https://example.com/">welcome</a>, see <a href="https://example.com/">again</a> and <a href="javascript:x()">this</a>.</p>` +
+	`<ul><li>one<ol><li>sub a</li><li>sub b</li></ol></li><li>two</li></ul>` +
+	`<table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>` +
+	`<p><img src="x.jpg" alt="Cover art"></p><script>evil()</script>end`
+
+func TestHTML2Text(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"whitespace", "  a \n\t b  ", "a b "},
+		{"entities", "Q&amp;A &lt;live&gt; &#x2122; &nbsp;", "Q&A <live> ™  "},
+		{"paragraphs", "<p>a</p><p>b</p>", "a\r\n\r\nb"},
+		{"line breaks", "a<br>b<br/><br>c", "a\r\nb\r\n\r\nc"},
+		{"link url", `see <a href="http://example.com/">here</a>`, "see http://example.com/"},
+		{"skipped", "<head><title>t</title></head>a<style>p{}</style><script>x()</script>b", "ab"},
+		{"unknown tags", "<span>a</span><foo>b</foo>", "ab"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, html2text.HTML2Text(tt.in))
+		})
+	}
+}
+
+func TestHTML2TextWithOptionsDefault(t *testing.T) {
+	t.Parallel()
+
+	got := html2text.HTML2TextWithOptions(showNotes, html2text.Options{LineBreak: "\n"})
+
+	assert.Equal(t, "Show notes\n\n"+
+		"Hello & https://example.com/, see https://example.com/ and .\n\n"+
+		"one\nsub a\nsub b\ntwo\n"+
+		"A B 1 2\n\n"+
+		"end", got)
+}
+
+func TestHTML2TextWithOptionsInline(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	opts := html2text.Options{
+		LineBreak:   "\n",
+		Links:       html2text.LinksInline,
+		Tables:      html2text.TablesRows,
+		Bullet:      "* ",
+		NumberLists: true,
+		ImageAlt:    true,
+	}
+
+	// act
+	got := html2text.HTML2TextWithOptions(showNotes, opts)
+
+	// assert
+	assert.Equal(t, "Show notes\n\n"+
+		"Hello & welcome (https://example.com/), see again (https://example.com/) and this.\n\n"+
+		"* one\n  1. sub a\n  2. sub b\n* two\n"+
+		"A | B\n1 | 2\n\n"+
+		"Cover art\n\n"+
+		"end", got)
+}
+
+func TestHTML2TextWithOptionsFootnotes(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	opts := html2text.Options{
+		Links:  html2text.LinksFootnotes,
+		Tables: html2text.TablesNone,
+		Width:  20,
+	}
+
+	// act
+	got := html2text.HTML2TextWithOptions(showNotes, opts)
+
+	// assert
+	assert.Equal(t, "Show notes\r\n\r\n"+
+		"Hello & welcome[1],\r\nsee again[1] and\r\nthis.\r\n\r\n"+
+		"one\r\nsub a\r\nsub b\r\ntwo\r\n\r\n"+
+		"end\r\n\r\n"+
+		"[1]\r\nhttps://example.com/", got)
+}
+
+func TestHTML2TextWithOptionsLinkText(t *testing.T) {
+	t.Parallel()
+
+	got := html2text.HTML2TextWithOptions(`<a href="http://example.com/">http://example.com/</a> or <a href="http://example.com/">text</a>`,
+		html2text.Options{Links: html2text.LinksInline})
+
+	assert.Equal(t, "http://example.com/ or text (http://example.com/)", got)
+	assert.Equal(t, "text", html2text.HTML2TextWithOptions(`<a href="http://example.com/">text</a>`,
+		html2text.Options{Links: html2text.LinksText}))
+}
@@ -0,0 +1,253 @@
+// Package htmlpolicy sanitizes the HTML of show notes against an
+// allowlist of elements and attributes, such as the small set Apple
+// Podcasts renders in descriptions.
+//
+// Elements that are not allowed are removed and their text kept, except
+// for script, style and similar elements that are removed with their
+// content.  Event handler attributes are never kept.
+package htmlpolicy
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/georgboe/rss-feed-generator/html2text"
+	"golang.org/x/net/html"
+)
+
+// Policy is the allowlist of the HTML kept by Sanitize.
+type Policy struct {
+	Name string
+
+	// Elements maps each allowed element to its allowed attributes.
+	Elements map[string][]string
+
+	// URLSchemes are the schemes allowed in href and src attributes.
+	// Relative URLs are not allowed.
+	URLSchemes []string
+
+	// LinkRel, when set, replaces the rel attribute of every link.
+	LinkRel string
+
+	// Text flattens the HTML to plain text with html2text instead.
+	Text bool
+}
+
+// Apple keeps the tags Apple Podcasts renders in descriptions.
+//
+// https://help.apple.com/itc/podcasts_connect/#/itcb54353390
+var Apple = Policy{
+	Name: "Apple Podcasts",
+	Elements: map[string][]string{
+		"p": nil, "br": nil, "ol": nil, "ul": nil, "li": nil,
+		"b": nil, "strong": nil, "i": nil, "em": nil,
+		"a": {"href"},
+	},
+	URLSchemes: []string{"http", "https", "mailto"},
+	LinkRel:    "noopener noreferrer",
+}
+
+// Spotify keeps the tags Spotify renders in episode descriptions.
+//
+// https://support.spotify.com/us/podcasters/article/formatting-your-episode-description/
+var Spotify = Policy{
+	Name: "Spotify",
+	Elements: map[string][]string{
+		"p": nil, "br": nil, "ol": nil, "ul": nil, "li": nil,
+		"b": nil, "strong": nil, "i": nil, "em": nil, "u": nil,
+		"h1": nil, "h2": nil, "h3": nil,
+		"a": {"href"},
+	},
+	URLSchemes: []string{"http", "https"},
+	LinkRel:    "noopener noreferrer",
+}
+
+// PlainText removes all markup.
+var PlainText = Policy{
+	Name: "Plain text",
+	Text: true,
+}
+
+// dropped are the elements removed with their content.
+var dropped = map[string]bool{
+	"script": true, "style": true, "head": true, "title": true,
+	"iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "svg": true, "math": true, "textarea": true,
+	"select": true,
+}
+
+// blocks are the elements whose removal leaves a line break, so that the
+// words on either side are not joined.
+var blocks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"br": true, "dd": true, "div": true, "dl": true, "dt": true,
+	"figcaption": true, "figure": true, "footer": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "td": true, "th": true,
+	"tr": true, "ul": true,
+}
+
+// void are the elements without content or end tag.
+var void = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// urlAttrs are the attributes holding a URL.
+var urlAttrs = map[string]bool{
+	"href": true, "src": true, "cite": true, "action": true, "poster": true,
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Sanitize returns the HTML with only the elements and attributes of the
+// Policy.  Open elements are closed, and end tags without a start tag
+// are removed.
+func (p Policy) Sanitize(s string) string {
+	if p.Text {
+		return strings.TrimSpace(html2text.HTML2Text(s))
+	}
+
+	var (
+		b    bytes.Buffer
+		open []string
+		drop []string
+	)
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		t := z.Token()
+
+		if len(drop) != 0 {
+			switch {
+			case tt == html.StartTagToken && t.Data == drop[len(drop)-1]:
+				drop = append(drop, t.Data)
+			case tt == html.EndTagToken && t.Data == drop[len(drop)-1]:
+				drop = drop[:len(drop)-1]
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			b.WriteString(textEscaper.Replace(t.Data))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if dropped[t.Data] {
+				if tt == html.StartTagToken && !void[t.Data] {
+					drop = append(drop, t.Data)
+				}
+				continue
+			}
+			if !p.allowed(t.Data) {
+				if blocks[t.Data] {
+					b.WriteByte('\n')
+				}
+				continue
+			}
+			b.WriteString(p.startTag(t))
+			switch {
+			case void[t.Data]:
+			case tt == html.SelfClosingTagToken:
+				// <p/> is not self-closing in HTML, so write the end tag
+				// rather than leave it open.
+				b.WriteString("</" + t.Data + ">")
+			default:
+				open = append(open, t.Data)
+			}
+
+		case html.EndTagToken:
+			if !p.allowed(t.Data) {
+				if blocks[t.Data] {
+					b.WriteByte('\n')
+				}
+				continue
+			}
+			n := lastIndex(open, t.Data)
+			if n < 0 {
+				continue
+			}
+			for len(open) > n {
+				b.WriteString("</" + open[len(open)-1] + ">")
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	for len(open) != 0 {
+		b.WriteString("</" + open[len(open)-1] + ">")
+		open = open[:len(open)-1]
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (p Policy) allowed(element string) bool {
+	_, ok := p.Elements[element]
+	return ok
+}
+
+// startTag writes the start tag with the allowed attributes, in the order
+// they appear, and the LinkRel of the Policy.
+func (p Policy) startTag(t html.Token) string {
+	var b strings.Builder
+	b.WriteString("<" + t.Data)
+	for _, a := range t.Attr {
+		if len(a.Namespace) != 0 || !p.allowedAttr(t.Data, a.Key) {
+			continue
+		}
+		if t.Data == "a" && a.Key == "rel" && len(p.LinkRel) != 0 {
+			continue
+		}
+		if urlAttrs[a.Key] && !p.allowedURL(a.Val) {
+			continue
+		}
+		b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+	}
+	if t.Data == "a" && len(p.LinkRel) != 0 {
+		b.WriteString(` rel="` + html.EscapeString(p.LinkRel) + `"`)
+	}
+	if void[t.Data] {
+		b.WriteString("/")
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+func (p Policy) allowedAttr(element, attr string) bool {
+	if strings.HasPrefix(attr, "on") {
+		return false
+	}
+	for _, a := range p.Elements[element] {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Policy) allowedURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || len(u.Scheme) == 0 {
+		return false
+	}
+	for _, scheme := range p.URLSchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func lastIndex(elements []string, element string) int {
+	for n := len(elements) - 1; n >= 0; n-- {
+		if elements[n] == element {
+			return n
+		}
+	}
+	return -1
+}
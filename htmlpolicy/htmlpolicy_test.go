@@ -0,0 +1,89 @@
+package htmlpolicy_test
+
+import (
+	"testing"
+
+	"github.com/georgboe/rss-feed-generator/htmlpolicy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeApple(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"allowed", `<p>Show <b>notes</b></p><ul><li>one</li></ul>`, `<p>Show <b>notes</b></p><ul><li>one</li></ul>`},
+		{"disallowed kept text", `<div><span class="x">Hello</span></div><div>World</div>`, "Hello\n\nWorld"},
+		{"script and style", `<p>a<script>alert("x")</script><style>p{}</style>b</p>`, `<p>ab</p>`},
+		{"event handlers", `<p onclick="evil()">a</p>`, `<p>a</p>`},
+		{"link rel", `<a href="https://example.com/" rel="opener" target="_blank">x</a>`, `<a href="https://example.com/" rel="noopener noreferrer">x</a>`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `<a rel="noopener noreferrer">x</a>`},
+		{"relative href", `<a href="/episodes/1">x</a>`, `<a rel="noopener noreferrer">x</a>`},
+		{"void", `a<br>b<br/>c<img src="http://example.com/i.jpg">`, `a<br/>b<br/>c`},
+		{"unclosed", `<p><b>bold`, `<p><b>bold</b></p>`},
+		{"self-closing", `<p/>a<b/>`, `<p></p>a<b></b>`},
+		{"stray end tag", `a</b></p>b`, `ab`},
+		{"misnested", `<b><i>x</b>y</i>`, `<b><i>x</i></b>y`},
+		{"text escaped", `Q&amp;A &lt;live&gt; & more`, `Q&amp;A &lt;live&gt; &amp; more`},
+		{"comment", `a<!-- hidden -->b`, `ab`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, htmlpolicy.Apple.Sanitize(tt.in))
+		})
+	}
+}
+
+func TestSanitizeIdempotent(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	in := `<h1>Title</h1><p>Q&amp;A with <a href="https://example.com/">guest</a> "quoted"</p>`
+
+	// act
+	once := htmlpolicy.Spotify.Sanitize(in)
+	twice := htmlpolicy.Spotify.Sanitize(once)
+
+	// assert
+	assert.Equal(t, `<h1>Title</h1><p>Q&amp;A with <a href="https://example.com/" rel="noopener noreferrer">guest</a> "quoted"</p>`, once)
+	assert.Equal(t, once, twice)
+}
+
+func TestSanitizeSpotifySchemes(t *testing.T) {
+	t.Parallel()
+
+	got := htmlpolicy.Spotify.Sanitize(`<a href="mailto:jane@example.com">mail</a>`)
+
+	assert.Equal(t, `<a rel="noopener noreferrer">mail</a>`, got)
+}
+
+func TestSanitizePlainText(t *testing.T) {
+	t.Parallel()
+
+	got := htmlpolicy.PlainText.Sanitize(`<p>Hello <b>World</b></p><script>x()</script>`)
+
+	assert.Equal(t, "Hello World", got)
+}
+
+func TestSanitizeCustomPolicy(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	policy := htmlpolicy.Policy{
+		Name:       "images",
+		Elements:   map[string][]string{"img": {"src", "alt", "onerror"}},
+		URLSchemes: []string{"https"},
+	}
+
+	// act
+	got := policy.Sanitize(`<img src="https://example.com/i.jpg" alt="art" onerror="x()" width="1"><img src="http://example.com/i.jpg">`)
+
+	// assert
+	assert.Equal(t, `<img src="https://example.com/i.jpg" alt="art"/><img/>`, got)
+}
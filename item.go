@@ -4,9 +4,12 @@ import (
 	"encoding/xml"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/georgboe/rss-feed-generator/html2text"
+	"github.com/pkg/errors"
 )
 
 // Item represents a single entry in a podcast.
@@ -23,7 +26,7 @@ import (
 //
 // Recommendations:
 // - Setting the minimal fields sets most of other fields, including iTunes.
-// - Use the Published time.Time setting instead of PubDate.
+// - Use SetPubDate with a time.Time instead of setting PubDate.
 // - Always set an Enclosure.Length, to be nice to your downloaders.
 // - Use Enclosure.Type instead of setting TypeFormatted for valid extensions.
 type Item struct {
@@ -34,9 +37,9 @@ type Item struct {
 	Description        *Description
 	EncodedDescription *EncodedContent
 	AuthorFormatted    string `xml:"author,omitempty"`
-	Category           string `xml:"category,omitempty"`
+	Categories         []*Category
 	Comments           string `xml:"comments,omitempty"`
-	Source             string `xml:"source,omitempty"`
+	Source             *Source
 	PubDate            string `xml:"pubDate,omitempty"`
 	Enclosure          *Enclosure
 
@@ -54,6 +57,34 @@ type Item struct {
 	IExplicit          string `xml:"itunes:explicit,omitempty"`
 	IIsClosedCaptioned string `xml:"itunes:isClosedCaptioned,omitempty"`
 	IOrder             string `xml:"itunes:order,omitempty"`
+
+	// https://support.google.com/podcast-publishers/answer/9889544?hl=en
+	GooglePlayAuthor      string `xml:"googleplay:author,omitempty"`
+	GooglePlayDescription string `xml:"googleplay:description,omitempty"`
+	GooglePlayImage       *GooglePlayImage
+	GooglePlayExplicit    string `xml:"googleplay:explicit,omitempty"`
+	GooglePlayBlock       string `xml:"googleplay:block,omitempty"`
+
+	// https://github.com/Podcastindex-org/podcast-namespace/blob/main/docs/1.0.md
+	PTranscripts         []*PodcastTranscript
+	PChapters            *PodcastChapters
+	PSoundbites          []*PodcastSoundbite
+	PPersons             []*PodcastPerson
+	PLocation            *PodcastLocation
+	PSeason              *PodcastSeason
+	PEpisode             *PodcastEpisode
+	PValue               *PodcastValue
+	PAlternateEnclosures []*PodcastAlternateEnclosure
+
+	// https://podlove.org/simple-chapters/
+	PSCChapters *PSCChapters
+
+	// Extensions are the elements of other namespaces, see
+	// ExtensionElement.
+	Extensions []*ExtensionElement
+
+	// Chapters are written by EncodeChapters and AddPodloveChapters.
+	Chapters []*Chapter `xml:"-"`
 }
 
 func (i *Item) AddGUID(guid string) {
@@ -83,6 +114,72 @@ func (i *Item) AddLink(link string) {
 	i.Link = link
 }
 
+// AddAlternateEnclosure adds a podcast:alternateEnclosure, another
+// variant of the episode media such as AAC or an HLS video stream.
+// Calling this method multiple times will APPEND the variant.
+//
+// Legacy clients only read the <enclosure>, so when no Enclosure is set
+// AddItem picks it from the variants: the Default one, or else the first
+// one, skipping those whose MIME type is not a registered EnclosureType.
+func (i *Item) AddAlternateEnclosure(enclosure PodcastAlternateEnclosure) {
+	if len(enclosure.Type) == 0 || len(enclosure.Sources) == 0 {
+		return
+	}
+
+	i.PAlternateEnclosures = append(i.PAlternateEnclosures, &enclosure)
+}
+
+// AddCategory adds the RSS category to the Item, with the optional
+// domain of its taxonomy.
+func (i *Item) AddCategory(category, domain string) {
+	if len(category) == 0 {
+		return
+	}
+	i.Categories = append(i.Categories, &Category{Domain: domain, Value: category})
+}
+
+// AddChapters links the podcast:chapters file of the episode.
+//
+// mimeType is usually "application/json+chapters".
+func (i *Item) AddChapters(url, mimeType string) {
+	if len(url) == 0 || len(mimeType) == 0 {
+		return
+	}
+
+	i.PChapters = &PodcastChapters{
+		URL:  url,
+		Type: mimeType,
+	}
+}
+
+// AddChapter adds a chapter marker to the episode.
+// Calling this method multiple times will APPEND the chapter.
+//
+// The chapter needs a title and a start time that is not negative and,
+// when the IDuration is set, falls within the episode.
+func (i *Item) AddChapter(chapter Chapter) error {
+	if len(chapter.Title) == 0 {
+		return errors.New("item.AddChapter: Title is required")
+	}
+	if chapter.StartTime < 0 {
+		return errors.Errorf("item.AddChapter: StartTime %v is negative", chapter.StartTime)
+	}
+	if i.chapterOutOfBounds(&chapter) {
+		return errors.Errorf("item.AddChapter: StartTime %v is beyond the duration %s", chapter.StartTime, i.IDuration)
+	}
+
+	i.Chapters = append(i.Chapters, &chapter)
+	return nil
+}
+
+// AddComments adds the URL of the comments page of the Item.
+func (i *Item) AddComments(url string) {
+	if len(url) == 0 {
+		return
+	}
+	i.Comments = url
+}
+
 func (i *Item) AddDescription(description Description) {
 	if len(description.Text) <= 0 {
 		return
@@ -105,6 +202,21 @@ func (i *Item) AddEnclosure(
 	}
 }
 
+// AddEnclosureURL adds the downloadable asset to the podcast Item,
+// inferring its EnclosureType from the file extension of the url.
+//
+// An error is returned when the extension is not registered, see
+// RegisterEnclosureType.
+func (i *Item) AddEnclosureURL(url string, lengthInBytes int64) error {
+	et, err := EnclosureTypeFromURL(url)
+	if err != nil {
+		return errors.Wrap(err, "item.AddEnclosureURL")
+	}
+
+	i.AddEnclosure(url, et, et.String(), lengthInBytes)
+	return nil
+}
+
 func (i *Item) AddEpisodeNumber(episodeNumber int64) {
 	if episodeNumber <= 0 {
 		return
@@ -138,6 +250,73 @@ func (i *Item) AddImage(url string) {
 	}
 }
 
+// AddGooglePlayAuthor adds the googleplay:author, falling back to the
+// iTunes author when empty.
+func (i *Item) AddGooglePlayAuthor(author string) {
+	if len(author) == 0 {
+		i.GooglePlayAuthor = i.IAuthor
+		return
+	}
+
+	i.GooglePlayAuthor = GenerateFeedString(author)
+}
+
+// AddGooglePlayBlock hides the episode from Google Podcasts when block is
+// "hide", falling back to the iTunes block when empty.
+func (i *Item) AddGooglePlayBlock(block string) {
+	switch {
+	case block == "hide":
+		i.GooglePlayBlock = "Yes"
+	case len(block) == 0 && len(i.IBlock) != 0:
+		i.GooglePlayBlock = i.IBlock
+	default:
+		i.GooglePlayBlock = "No"
+	}
+}
+
+// AddGooglePlayDescription adds the plain text googleplay:description,
+// falling back to the iTunes summary or description when empty.
+//
+// Limit: 4000 characters
+func (i *Item) AddGooglePlayDescription(description string) {
+	switch {
+	case len(description) != 0:
+	case i.ISummary != nil:
+		description = i.ISummary.Text
+	case i.Description != nil:
+		description = i.Description.Text
+	}
+
+	i.GooglePlayDescription = truncateRunes(html2text.HTML2Text(description), 4000)
+}
+
+// AddGooglePlayExplicit sets googleplay:explicit from the parental
+// advisory, falling back to the iTunes explicit flag when it is neither
+// ParentalAdvisoryExplicit nor ParentalAdvisoryClean.
+func (i *Item) AddGooglePlayExplicit(parentalAdvisory string) {
+	switch parentalAdvisory {
+	case ParentalAdvisoryExplicit:
+		i.GooglePlayExplicit = "yes"
+	case ParentalAdvisoryClean:
+		i.GooglePlayExplicit = "no"
+	default:
+		i.GooglePlayExplicit = i.IExplicit
+	}
+}
+
+// AddGooglePlayImage adds the googleplay:image, falling back to the
+// iTunes image when empty.
+func (i *Item) AddGooglePlayImage(url string) {
+	if len(url) == 0 && i.IImage != nil {
+		url = i.IImage.HREF
+	}
+	if len(url) == 0 {
+		return
+	}
+
+	i.GooglePlayImage = &GooglePlayImage{HREF: url}
+}
+
 func (i *Item) AddItunesBlock(block string) {
 	if block == "hide" {
 		i.IBlock = "Yes"
@@ -154,6 +333,22 @@ func (i *Item) AddItunesTitle(title string) {
 	i.ITitle = title
 }
 
+// AddLocation adds the podcast:location the episode is about.
+//
+// geo is a "geo:" URI (RFC 5870) and osm an OpenStreetMap identifier,
+// both optional.
+func (i *Item) AddLocation(name, geo, osm string) {
+	if len(name) == 0 {
+		return
+	}
+
+	i.PLocation = &PodcastLocation{
+		Name: GenerateFeedString(name),
+		Geo:  geo,
+		OSM:  osm,
+	}
+}
+
 func (i *Item) AddParentalAdvisory(parentalAdvisory string) {
 	if parentalAdvisory == ParentalAdvisoryExplicit {
 		i.IExplicit = "yes"
@@ -164,13 +359,91 @@ func (i *Item) AddParentalAdvisory(parentalAdvisory string) {
 	return
 }
 
-func (i *Item) AddPubDate(datetime string) {
+// AddPerson adds a podcast:person, such as a guest, to the episode.
+// Calling this method multiple times will APPEND the person.
+func (i *Item) AddPerson(person PodcastPerson) {
+	if len(person.Name) == 0 {
+		return
+	}
+
+	person.Name = GenerateFeedString(person.Name)
+	i.PPersons = append(i.PPersons, &person)
+}
+
+// AddPodcastEpisode sets the podcast:episode number, which may be a
+// decimal such as 204.5.  display optionally overrides how the number is
+// shown by apps.
+func (i *Item) AddPodcastEpisode(number float64, display string) {
+	if number <= 0 {
+		return
+	}
+
+	i.PEpisode = &PodcastEpisode{
+		Number:  number,
+		Display: GenerateFeedString(display),
+	}
+}
+
+// AddPodcastSeason sets the podcast:season number with an optional name.
+func (i *Item) AddPodcastSeason(number int64, name string) {
+	if number <= 0 {
+		return
+	}
+
+	i.PSeason = &PodcastSeason{
+		Number: number,
+		Name:   GenerateFeedString(name),
+	}
+}
 
+// AddPodloveChapters writes the chapters inline as Podlove Simple
+// Chapters (psc:chapters) for apps that do not read podcast:chapters.
+// Hidden chapters are left out.
+func (i *Item) AddPodloveChapters() {
+	chapters := &PSCChapters{
+		XMLNS:   PSCNS,
+		Version: "1.2",
+	}
+	for _, c := range i.sortedChapters() {
+		if c.Hidden {
+			continue
+		}
+		chapters.Chapters = append(chapters.Chapters, &PSCChapter{
+			Start: formatNormalPlayTime(c.StartTime),
+			Title: c.Title,
+			Href:  c.URL,
+			Image: c.Img,
+		})
+	}
+	if len(chapters.Chapters) == 0 {
+		return
+	}
+
+	i.PSCChapters = chapters
+}
+
+// AddPubDate sets the pubDate from an RFC 2822 formatted string,
+// normalised to RFC 1123Z.  Other strings are set as they are.
+func (i *Item) AddPubDate(datetime string) {
 	if len(datetime) == 0 {
 		return
 	}
+	t, err := parsePubDate(datetime)
+	if err != nil {
+		i.PubDate = datetime
+		return
+	}
 
-	i.PubDate = datetime
+	i.SetPubDate(t)
+}
+
+// SetPubDate sets the pubDate, formatted as RFC 1123Z.
+func (i *Item) SetPubDate(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+
+	i.PubDate = t.Format(time.RFC1123Z)
 }
 
 func (i *Item) AddSeasonNumber(seasonNumber int64) {
@@ -181,6 +454,29 @@ func (i *Item) AddSeasonNumber(seasonNumber int64) {
 	i.SeasonNumber = strconv.FormatInt(seasonNumber, 10)
 }
 
+// AddSoundbite adds a podcast:soundbite for the segment starting at
+// startTime and lasting duration seconds.  Calling this method multiple
+// times will APPEND the soundbite.
+func (i *Item) AddSoundbite(startTime, duration float64, title string) {
+	if startTime < 0 || duration <= 0 {
+		return
+	}
+
+	i.PSoundbites = append(i.PSoundbites, &PodcastSoundbite{
+		StartTime: startTime,
+		Duration:  duration,
+		Title:     GenerateFeedString(title),
+	})
+}
+
+// AddSource adds the RSS channel the Item came from.
+func (i *Item) AddSource(title, url string) {
+	if len(url) == 0 {
+		return
+	}
+	i.Source = &Source{URL: url, Title: GenerateFeedString(title)}
+}
+
 // AddSummary adds the iTunes summary.
 //
 // Limit: 4000 characters
@@ -198,6 +494,66 @@ func (i *Item) AddSummary(summary string) {
 	}
 }
 
+// AddTranscript links a podcast:transcript file of the episode.
+// Calling this method multiple times will APPEND the transcript, allowing
+// one per format or language.
+//
+// mimeType is for example "text/vtt", "application/x-subrip",
+// "application/json" or "text/html".  Set rel to "captions" for
+// caption-grade files.
+func (i *Item) AddTranscript(url, mimeType, language, rel string) {
+	if len(url) == 0 || len(mimeType) == 0 {
+		return
+	}
+
+	i.PTranscripts = append(i.PTranscripts, &PodcastTranscript{
+		URL:      url,
+		Type:     mimeType,
+		Language: language,
+		Rel:      rel,
+	})
+}
+
+// AddValue sets the podcast:value block of the episode, overriding the
+// split of the show, after checking it with PodcastValue.Validate.
+func (i *Item) AddValue(value PodcastValue) error {
+	if err := value.Validate(); err != nil {
+		return errors.Wrap(err, "item.AddValue: invalid value")
+	}
+	i.PValue = &value
+	return nil
+}
+
+// AddMedia adds the media described by info, such as the result of
+// ProbeMedia, as the downloadable asset at url.
+//
+// The Enclosure type and length and the duration are set from info, and
+// the Title too when it is still empty.  The embedded artwork is left to
+// the caller, as it has to be hosted before AddImage can link it.
+func (i *Item) AddMedia(url string, info *MediaInfo) {
+	if len(url) == 0 || info == nil {
+		return
+	}
+
+	i.AddEnclosure(url, info.Type, info.Type.String(), info.Size)
+	i.SetDuration(info.Duration)
+	if len(i.Title) == 0 {
+		i.AddTitle(info.Title)
+	}
+}
+
+// AddMediaFile probes the local media file and adds it as the
+// downloadable asset at url, see AddMedia.
+func (i *Item) AddMediaFile(url, filename string) error {
+	info, err := ProbeMediaFile(filename)
+	if err != nil {
+		return errors.Wrap(err, "item.AddMediaFile")
+	}
+
+	i.AddMedia(url, info)
+	return nil
+}
+
 // AddDuration adds the duration to the iTunes duration field.
 func (i *Item) AddDuration(durationInSeconds int64) {
 	if durationInSeconds <= 0 {
@@ -206,6 +562,32 @@ func (i *Item) AddDuration(durationInSeconds int64) {
 	i.IDuration = fmt.Sprint(durationInSeconds)
 }
 
+// SetDuration sets the iTunes duration field to d, in seconds rounded to
+// the nearest second.  Podcast.DurationFormat may reformat it as the
+// Item is encoded.
+func (i *Item) SetDuration(d time.Duration) {
+	i.AddDuration(int64(d.Round(time.Second) / time.Second))
+}
+
+// Duration parses the iTunes duration field, see ParseDuration.
+func (i *Item) Duration() (time.Duration, error) {
+	d, err := ParseDuration(i.IDuration)
+	if err != nil {
+		return 0, errors.Wrap(err, "item.Duration")
+	}
+	return d, nil
+}
+
+// durationSeconds parses an itunes:duration written as seconds, MM:SS or
+// H:MM:SS back into whole seconds.
+func durationSeconds(duration string) (int64, error) {
+	d, err := ParseDuration(duration)
+	if err != nil {
+		return 0, err
+	}
+	return int64(d / time.Second), nil
+}
+
 var parseDuration = func(duration int64) string {
 	h := duration / 3600
 	duration = duration % 3600
@@ -233,3 +615,49 @@ var parseDuration = func(duration int64) string {
 	// M:SS
 	return fmt.Sprintf("%d:%02d", m, s)
 }
+
+// primaryEnclosure returns the <enclosure> for legacy clients from the
+// alternate enclosures, or nil when none of them can be used.  Only an
+// http:// or https:// source can be, as legacy clients cannot fetch the
+// others (IPFS, torrents and the like).
+func (i *Item) primaryEnclosure() *Enclosure {
+	var primary *PodcastAlternateEnclosure
+	var url string
+	for _, ae := range i.PAlternateEnclosures {
+		if _, err := EnclosureTypeByMIME(ae.Type); err != nil {
+			continue
+		}
+		u := ae.httpSource()
+		if len(u) == 0 {
+			continue
+		}
+		if ae.Default {
+			primary, url = ae, u
+			break
+		}
+		if primary == nil {
+			primary, url = ae, u
+		}
+	}
+	if primary == nil {
+		return nil
+	}
+
+	et, _ := EnclosureTypeByMIME(primary.Type)
+	return &Enclosure{
+		URL:    url,
+		Type:   et,
+		Length: primary.Length,
+	}
+}
+
+// httpSource returns the first http:// or https:// source URI of the
+// alternate enclosure, or "" when it has none.
+func (e *PodcastAlternateEnclosure) httpSource() string {
+	for _, s := range e.Sources {
+		if strings.HasPrefix(s.URI, "http://") || strings.HasPrefix(s.URI, "https://") {
+			return s.URI
+		}
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package podcast_test
 
 import (
 	"testing"
+	"time"
 
 	podcast "github.com/georgboe/rss-feed-generator"
 	"github.com/stretchr/testify/assert"
@@ -246,6 +247,26 @@ func TestAddPubDate(t *testing.T) {
 	assert.Equal(t, i.PubDate, "Sun, 14 Mar 2021 18:34:05 +0000")
 }
 
+func TestAddPubDateInvalid(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddPubDate("2021-03-14")
+
+	assert.Equal(t, "2021-03-14", i.PubDate)
+}
+
+func TestSetPubDate(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.SetPubDate(time.Date(2021, time.March, 14, 18, 34, 5, 0, time.UTC))
+
+	assert.Equal(t, "Sun, 14 Mar 2021 18:34:05 +0000", i.PubDate)
+}
+
 func TestItemAddSummaryTooLong(t *testing.T) {
 	t.Parallel()
 
@@ -425,3 +446,113 @@ func TestItemAddDurationLessThanZero(t *testing.T) {
 	// assert
 	assert.EqualValues(t, "", i.IDuration)
 }
+
+func TestAddTranscriptEmpty(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddTranscript("", "text/vtt", "en", "")
+	i.AddTranscript("http://example.com/1.vtt", "", "en", "")
+
+	assert.Len(t, i.PTranscripts, 0)
+}
+
+func TestAddTranscript(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddTranscript("http://example.com/1.vtt", "text/vtt", "en", "captions")
+	i.AddTranscript("http://example.com/1.srt", "application/x-subrip", "en", "")
+
+	assert.Len(t, i.PTranscripts, 2)
+	assert.Equal(t, "captions", i.PTranscripts[0].Rel)
+}
+
+func TestAddChaptersEmpty(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddChapters("", "application/json+chapters")
+
+	assert.Nil(t, i.PChapters)
+}
+
+func TestAddSoundbiteInvalid(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddSoundbite(-1, 30, "")
+	i.AddSoundbite(10, 0, "")
+
+	assert.Len(t, i.PSoundbites, 0)
+}
+
+func TestAddPodcastSeasonInvalid(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddPodcastSeason(0, "name")
+
+	assert.Nil(t, i.PSeason)
+}
+
+func TestAddPodcastEpisode(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddPodcastEpisode(3, "Ch.3")
+
+	assert.EqualValues(t, 3, i.PEpisode.Number)
+	assert.Equal(t, "Ch.3", i.PEpisode.Display)
+}
+
+func TestItemAddPerson(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddPerson(podcast.PodcastPerson{Name: "John Smith", Role: "guest", Href: "https://example.com/john"})
+
+	assert.Len(t, i.PPersons, 1)
+	assert.Equal(t, "guest", i.PPersons[0].Role)
+}
+
+func TestItemAddAlternateEnclosureWithoutSource(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	i.AddAlternateEnclosure(podcast.PodcastAlternateEnclosure{Type: "audio/aac"})
+
+	assert.Len(t, i.PAlternateEnclosures, 0)
+}
+
+func TestItemAddEnclosureURL(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	err := i.AddEnclosureURL("http://example.com/1.flac", 2048)
+
+	assert.NoError(t, err)
+	assert.Equal(t, podcast.FLAC, i.Enclosure.Type)
+	assert.Equal(t, "audio/flac", i.Enclosure.TypeFormatted)
+	assert.EqualValues(t, 2048, i.Enclosure.Length)
+}
+
+func TestItemAddEnclosureURLUnknown(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	err := i.AddEnclosureURL("http://example.com/1.xyz", 2048)
+
+	assert.Error(t, err)
+	assert.Nil(t, i.Enclosure)
+}
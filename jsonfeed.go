@@ -0,0 +1,136 @@
+package podcast
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	jsonfeed "github.com/georgboe/rss-feed-generator/parser/json"
+	"github.com/pkg/errors"
+)
+
+// JSONFeedVersion is the version URL of the JSON Feed documents written by
+// Podcast.EncodeJSONFeed.
+const JSONFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// EncodeJSONFeed writes the bytes to the io.Writer stream in JSON Feed 1.1
+// specification.
+//
+// Each Item becomes a JSON Feed item whose Enclosure and IDuration make up
+// the attachment.  IImage is used for both the feed icon and the item
+// image, and a complete show (IComplete) is marked as expired.
+//
+// feedURL is where the JSON document itself is published and is written
+// as its feed_url; it is left out when empty.  An Item without a GUID or
+// Link is identified by its Enclosure URL, and one with none of them is an
+// error, as JSON Feed requires an id for every item.
+//
+// https://www.jsonfeed.org/version/1.1/
+func (p *Podcast) EncodeJSONFeed(w io.Writer, feedURL string) error {
+	f, err := p.encodable().jsonFeed(feedURL)
+	if err != nil {
+		return err
+	}
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(f); err != nil {
+		return errors.Wrap(err, "podcast.EncodeJSONFeed: e.Encode returned error")
+	}
+	return nil
+}
+
+// jsonFeedDocument is the jsonfeed.Feed written by EncodeJSONFeed.
+type jsonFeedDocument struct {
+	*jsonfeed.Feed
+	Items []*jsonFeedItem `json:"items"`
+}
+
+// jsonFeedItem is a jsonfeed.Item that writes an empty content_text when
+// it has no content_html, as JSON Feed 1.1 requires one of them.
+type jsonFeedItem struct {
+	*jsonfeed.Item
+	ContentText *string `json:"content_text,omitempty"`
+}
+
+func (p *Podcast) jsonFeed(feedURL string) (*jsonFeedDocument, error) {
+	f := &jsonFeedDocument{
+		Feed: &jsonfeed.Feed{
+			Version:     JSONFeedVersion,
+			Title:       p.Title,
+			HomePageURL: p.Link,
+			FeedURL:     feedURL,
+			Language:    p.Language,
+			Expired:     p.IComplete == "Yes",
+		},
+		Items: []*jsonFeedItem{},
+	}
+	if p.Description != nil {
+		f.Description = p.Description.Text
+	}
+	if p.IImage != nil {
+		f.Icon = p.IImage.HREF
+	}
+	if len(p.IAuthor) != 0 {
+		f.Authors = []*jsonfeed.Author{{Name: p.IAuthor}}
+	}
+	for n, i := range p.Items {
+		item := i.jsonFeedItem()
+		if len(item.ID) == 0 {
+			return nil, errors.Errorf("podcast.EncodeJSONFeed: Items[%d] has no GUID, Link or Enclosure", n)
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+func (i *Item) jsonFeedItem() *jsonFeedItem {
+	ji := &jsonfeed.Item{
+		ID:    i.Link,
+		URL:   i.Link,
+		Title: i.Title,
+	}
+	if i.GUID != nil {
+		ji.ID = i.GUID.Value
+	}
+	if len(ji.ID) == 0 && i.Enclosure != nil {
+		ji.ID = i.Enclosure.URL
+	}
+	switch {
+	case i.EncodedDescription != nil:
+		ji.ContentHTML = i.EncodedDescription.Text
+	case i.Description != nil:
+		ji.ContentHTML = i.Description.Text
+	}
+	if i.ISummary != nil {
+		ji.Summary = i.ISummary.Text
+	}
+	if i.IImage != nil {
+		ji.Image = i.IImage.HREF
+	}
+	if t, err := parsePubDate(i.PubDate); err == nil {
+		ji.DatePublished = t.Format(time.RFC3339)
+	}
+	if len(i.IAuthor) != 0 {
+		ji.Authors = []*jsonfeed.Author{{Name: i.IAuthor}}
+	}
+	for _, c := range i.Categories {
+		ji.Tags = append(ji.Tags, c.Value)
+	}
+	if i.Enclosure != nil {
+		attachment := jsonfeed.Attachments{
+			URL:         i.Enclosure.URL,
+			MimeType:    i.Enclosure.TypeFormatted,
+			SizeInBytes: i.Enclosure.Length,
+		}
+		if d, err := durationSeconds(i.IDuration); err == nil {
+			attachment.DurationInSeconds = d
+		}
+		ji.Attachments = &[]jsonfeed.Attachments{attachment}
+	}
+	item := &jsonFeedItem{Item: ji}
+	if len(ji.ContentHTML) == 0 {
+		item.ContentText = new(string)
+	}
+	return item
+}
@@ -0,0 +1,113 @@
+package podcast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	jsonfeed "github.com/georgboe/rss-feed-generator/parser/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeJSONFeed(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("Sample Podcasts", "http://example.com/", podcast.Description{Text: "An example Podcast"}, nil, nil)
+	p.AddAuthor([]string{"Jane Doe"})
+	p.AddImage("http://example.com/podcast.jpg")
+	p.AddLanguage("en")
+
+	i := podcast.Item{Title: "Episode 1"}
+	i.AddDescription(podcast.Description{Text: "<p>Show notes</p>"})
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 183)
+	i.AddPubDate("Sun, 14 Mar 2021 18:34:05 +0000")
+	i.AddDuration(1800)
+	if _, err := p.AddItem(i); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	var b bytes.Buffer
+	err := p.EncodeJSONFeed(&b, "http://example.com/feed.json")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `"content_html": "<p>Show notes</p>"`)
+
+	var f jsonfeed.Feed
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &f))
+	assert.Equal(t, podcast.JSONFeedVersion, f.Version)
+	assert.Equal(t, "http://example.com/podcast.jpg", f.Icon)
+	assert.Equal(t, "Jane Doe", f.Authors[0].Name)
+	assert.Len(t, f.Items, 1)
+	assert.Equal(t, "http://example.com/1.mp3", f.Items[0].ID)
+	assert.Equal(t, "2021-03-14T18:34:05Z", f.Items[0].DatePublished)
+	assert.Equal(t, "http://example.com/podcast.jpg", f.Items[0].Image)
+	attachments := *f.Items[0].Attachments
+	assert.Equal(t, "audio/mpeg", attachments[0].MimeType)
+	assert.EqualValues(t, 183, attachments[0].SizeInBytes)
+	assert.EqualValues(t, 1800, attachments[0].DurationInSeconds)
+}
+
+func TestEncodeJSONFeedRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.AddAtomLink("http://example.com/feed.rss")
+	i := &podcast.Item{Title: "Episode 1"}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 183)
+	p.Items = append(p.Items, i)
+
+	// act
+	var b bytes.Buffer
+	err := p.EncodeJSONFeed(&b, "http://example.com/feed.json")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `"feed_url": "http://example.com/feed.json"`)
+	assert.Contains(t, b.String(), `"id": "http://example.com/1.mp3"`)
+	assert.Contains(t, b.String(), `"content_text": ""`)
+}
+
+func TestEncodeJSONFeedEmpty(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: ""}, nil, nil)
+
+	var b bytes.Buffer
+	err := p.EncodeJSONFeed(&b, "")
+
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `"items": []`)
+	assert.NotContains(t, b.String(), `"feed_url"`)
+}
+
+func TestEncodeJSONFeedWriterError(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: ""}, nil, nil)
+
+	err := p.EncodeJSONFeed(&errWriter{}, "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "e.Encode returned error")
+}
+
+func TestEncodeJSONFeedRequiresItemID(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.Items = append(p.Items, &podcast.Item{Title: "Episode 1"})
+
+	// act
+	var b bytes.Buffer
+	err := p.EncodeJSONFeed(&b, "http://example.com/feed.json")
+
+	// assert
+	assert.EqualError(t, err, "podcast.EncodeJSONFeed: Items[0] has no GUID, Link or Enclosure")
+	assert.Empty(t, b.String())
+}
@@ -0,0 +1,87 @@
+package podcast
+
+import (
+	"encoding/xml"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: https://tools.ietf.org/html/rfc5005
+//
+
+// Paging link relations written by Podcast.Pages.
+const (
+	RelFirst = "first"
+	RelNext  = "next"
+	RelPrev  = "prev"
+	RelLast  = "last"
+)
+
+// FHArchive flags a document that holds the complete, archived history
+// of the feed.
+type FHArchive struct {
+	XMLName xml.Name `xml:"fh:archive"`
+}
+
+// Pages splits the Items into documents of at most size Items each, in
+// the order they are stored in the Podcast.
+//
+// pageURL returns the URL of the 1-based page number.  Each page is a
+// shallow copy of the Podcast whose AtomLink points to its own URL and
+// whose PagingLinks point to the first, previous, next and last pages
+// as they apply.  A Podcast without Items returns a single empty page.
+func (p *Podcast) Pages(size int, pageURL func(page int) string) ([]*Podcast, error) {
+	if size <= 0 {
+		return nil, errors.New("podcast.Pages: size must be greater than zero")
+	}
+	if pageURL == nil {
+		return nil, errors.New("podcast.Pages: pageURL is required")
+	}
+
+	count := (len(p.Items) + size - 1) / size
+	if count == 0 {
+		count = 1
+	}
+	pages := make([]*Podcast, 0, count)
+	for n := 1; n <= count; n++ {
+		start := (n - 1) * size
+		end := start + size
+		if end > len(p.Items) {
+			end = len(p.Items)
+		}
+
+		page := *p
+		page.Items = p.Items[start:end:end]
+		page.PagingLinks = nil
+		page.AddAtomLink(pageURL(n))
+		page.AddPagingLink(RelFirst, pageURL(1))
+		if n > 1 {
+			page.AddPagingLink(RelPrev, pageURL(n-1))
+		}
+		if n < count {
+			page.AddPagingLink(RelNext, pageURL(n+1))
+		}
+		page.AddPagingLink(RelLast, pageURL(count))
+		pages = append(pages, &page)
+	}
+	return pages, nil
+}
+
+// Archive returns a shallow copy of the Podcast holding all of its Items,
+// flagged with fh:archive and linking to itself at href, so podcast apps
+// can backfill the history of a paged feed.
+func (p *Podcast) Archive(href string) *Podcast {
+	archive := *p
+	archive.PagingLinks = nil
+	archive.AddAtomLink(href)
+	archive.FHArchive = &FHArchive{}
+	return &archive
+}
+
+// fhNS returns the feed history namespace when the Podcast uses it.
+func (p *Podcast) fhNS() string {
+	if p.FHArchive == nil {
+		return ""
+	}
+	return FHNS
+}
@@ -0,0 +1,161 @@
+package podcast_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPagedPodcast(t *testing.T, items int) podcast.Podcast {
+	t.Helper()
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, nil, nil)
+	for x := 1; x <= items; x++ {
+		i := podcast.Item{
+			Title:       fmt.Sprintf("Episode %d", x),
+			Link:        fmt.Sprintf("http://example.com/%d.mp3", x),
+			Description: &podcast.Description{Text: "Description"},
+		}
+		if _, err := p.AddItem(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func pageURL(page int) string {
+	return fmt.Sprintf("http://example.com/feed.rss?page=%d", page)
+}
+
+func pagingRels(p *podcast.Podcast) map[string]string {
+	rels := map[string]string{}
+	for _, l := range p.PagingLinks {
+		rels[l.Rel] = l.HREF
+	}
+	return rels
+}
+
+func TestPages(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newPagedPodcast(t, 5)
+
+	// act
+	pages, err := p.Pages(2, pageURL)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, pages, 3)
+	assert.Len(t, pages[0].Items, 2)
+	assert.Len(t, pages[1].Items, 2)
+	assert.Len(t, pages[2].Items, 1)
+	assert.Equal(t, "Episode 5", pages[2].Items[0].Title)
+	assert.Len(t, p.Items, 5)
+	assert.Nil(t, p.AtomLink)
+
+	assert.Equal(t, pageURL(1), pages[0].AtomLink.HREF)
+	assert.Equal(t, "self", pages[0].AtomLink.Rel)
+	assert.Equal(t, map[string]string{
+		podcast.RelFirst: pageURL(1),
+		podcast.RelNext:  pageURL(2),
+		podcast.RelLast:  pageURL(3),
+	}, pagingRels(pages[0]))
+	assert.Equal(t, map[string]string{
+		podcast.RelFirst: pageURL(1),
+		podcast.RelPrev:  pageURL(1),
+		podcast.RelNext:  pageURL(3),
+		podcast.RelLast:  pageURL(3),
+	}, pagingRels(pages[1]))
+	assert.Equal(t, map[string]string{
+		podcast.RelFirst: pageURL(1),
+		podcast.RelPrev:  pageURL(2),
+		podcast.RelLast:  pageURL(3),
+	}, pagingRels(pages[2]))
+}
+
+func TestPagesNoItems(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newPagedPodcast(t, 0)
+
+	// act
+	pages, err := p.Pages(10, pageURL)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, pages, 1)
+	assert.Len(t, pages[0].Items, 0)
+	assert.Equal(t, map[string]string{
+		podcast.RelFirst: pageURL(1),
+		podcast.RelLast:  pageURL(1),
+	}, pagingRels(pages[0]))
+}
+
+func TestPagesInvalidSize(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newPagedPodcast(t, 1)
+
+	// act
+	pages, err := p.Pages(0, pageURL)
+
+	// assert
+	assert.Error(t, err)
+	assert.Nil(t, pages)
+}
+
+func TestPagesEncode(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newPagedPodcast(t, 3)
+	pages, err := p.Pages(2, pageURL)
+	assert.NoError(t, err)
+
+	// act
+	var b bytes.Buffer
+	err = pages[1].Encode(&b)
+
+	// assert
+	assert.NoError(t, err)
+	out := b.String()
+	assert.Contains(t, out, `<atom:link href="http://example.com/feed.rss?page=2" rel="self" type="application/rss+xml"></atom:link>`)
+	assert.Contains(t, out, `<atom:link href="http://example.com/feed.rss?page=1" rel="prev" type="application/rss+xml"></atom:link>`)
+	assert.NotContains(t, out, "fh:")
+}
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newPagedPodcast(t, 3)
+
+	// act
+	a := p.Archive("http://example.com/archive.rss")
+	var b bytes.Buffer
+	err := a.Encode(&b)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, a.Items, 3)
+	assert.Nil(t, p.FHArchive)
+	out := b.String()
+	assert.Contains(t, out, `xmlns:fh="http://purl.org/syndication/history/1.0"`)
+	assert.Contains(t, out, `<fh:archive></fh:archive>`)
+	assert.Contains(t, out, `<atom:link href="http://example.com/archive.rss" rel="self" type="application/rss+xml"></atom:link>`)
+}
+
+func TestAddPagingLinkEmpty(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.Podcast{}
+
+	p.AddPagingLink(podcast.RelNext, "")
+
+	assert.Len(t, p.PagingLinks, 0)
+}
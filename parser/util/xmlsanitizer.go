@@ -6,17 +6,23 @@ import (
 	"golang.org/x/text/transform"
 )
 
+// IsXMLChar reports whether r is a legal character in XML 1.0, see
+// https://www.w3.org/TR/xml/#charsets
+func IsXMLChar(r rune) bool {
+	return r == 0x09 ||
+		r == 0x0A ||
+		r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
 // NewXMLSanitizerReader creates an io.Reader that
 // wraps another io.Reader and removes illegal xml
 // characters from the io stream.
 func NewXMLSanitizerReader(xml io.Reader) io.Reader {
 	isIllegal := func(r rune) bool {
-		return !(r == 0x09 ||
-			r == 0x0A ||
-			r == 0x0D ||
-			r >= 0x20 && r <= 0xDF77 ||
-			r >= 0xE000 && r <= 0xFFFD ||
-			r >= 0x10000 && r <= 0x10FFFF)
+		return !IsXMLChar(r)
 	}
 	t := transform.Chain(transform.RemoveFunc(isIllegal))
 	return transform.NewReader(xml, t)
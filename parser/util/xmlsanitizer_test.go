@@ -0,0 +1,42 @@
+package util
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsXMLChar(t *testing.T) {
+	tests := []struct {
+		r     rune
+		legal bool
+	}{
+		{'\t', true},
+		{'\n', true},
+		{'\r', true},
+		{0x00, false},
+		{0x08, false},
+		{'a', true},
+		{0xD7FF, true},
+		{0xD800, false},
+		{0xDFFF, false},
+		{0xE000, true},
+		{0xFFFE, false},
+		{0x1F600, true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.legal, IsXMLChar(test.r), "%U", test.r)
+	}
+}
+
+func TestNewXMLSanitizerReader(t *testing.T) {
+	r := NewXMLSanitizerReader(strings.NewReader("<a>b\x00c\x1b퟿</a>"))
+
+	b, err := ioutil.ReadAll(r)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "<a>bc퟿</a>", string(b))
+}
@@ -5,43 +5,48 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
-	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/georgboe/rss-feed-generator/html2text"
+	"github.com/georgboe/rss-feed-generator/htmlpolicy"
+	"github.com/georgboe/rss-feed-generator/taxonomy"
 	"github.com/pkg/errors"
 )
 
 // Constants to use while generating podcast feed.
 const (
-	pVersion  = "1.3.1"
-	HEADER    = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"
-	ATOMNS    = "http://www.w3.org/2005/Atom"
-	ITUNESNS  = "http://www.itunes.com/dtds/podcast-1.0.dtd"
-	PODCASTNS = "https://podcastindex.org/namespace/1.0"
-	CONTENT   = "http://purl.org/rss/1.0/modules/content/"
+	pVersion     = "1.3.1"
+	HEADER       = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"
+	ATOMNS       = "http://www.w3.org/2005/Atom"
+	ITUNESNS     = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+	PODCASTNS    = "https://podcastindex.org/namespace/1.0"
+	CONTENT      = "http://purl.org/rss/1.0/modules/content/"
+	GOOGLEPLAYNS = "http://www.google.com/schemas/play-podcasts/1.0"
+	FHNS         = "http://purl.org/syndication/history/1.0"
 )
 
 // Podcast represents a podcast.
 type Podcast struct {
 	XMLName        xml.Name `xml:"channel"`
 	AtomLink       *AtomLink
+	PagingLinks    []*AtomLink
 	Generator      string `xml:"generator,omitempty"`
 	Title          string `xml:"title"`
 	Link           string `xml:"link,omitempty"`
 	Description    *Description
 	Language       string `xml:"language,omitempty"`
-	Cloud          string `xml:"cloud,omitempty"`
+	Cloud          *Cloud
 	Copyright      string `xml:"copyright,omitempty"`
 	Docs           string `xml:"docs,omitempty"`
 	PubDate        string `xml:"pubDate,omitempty"`
 	LastBuildDate  string `xml:"lastBuildDate,omitempty"`
 	ManagingEditor string `xml:"managingEditor,omitempty"`
 	Rating         string `xml:"rating,omitempty"`
-	SkipHours      string `xml:"skipHours,omitempty"`
-	SkipDays       string `xml:"skipDays,omitempty"`
+	SkipHours      *SkipHours
+	SkipDays       *SkipDays
 	TTL            int    `xml:"ttl,omitempty"`
 	WebMaster      string `xml:"webMaster,omitempty"`
 	Image          *Image
@@ -63,14 +68,44 @@ type Podcast struct {
 	ICategories []*ICategory
 
 	// https://support.google.com/podcast-publishers/answer/9889544?hl=en
-	// GooglePlayAuthor      string `xml:"googleplay:author,omitempty"`
-	// GooglePlayDescription string `xml:"googleplay:description,omitempty"`
-	// GooglePlayOwner       string `xml:"googleplay:owner,omitempty"`
-	// GooglePlayImage       *GooglePlayImage
+	GooglePlayAuthor      string `xml:"googleplay:author,omitempty"`
+	GooglePlayDescription string `xml:"googleplay:description,omitempty"`
+	GooglePlayOwner       string `xml:"googleplay:owner,omitempty"`
+	GooglePlayImage       *GooglePlayImage
+	GooglePlayCategories  []*GooglePlayCategory
+	GooglePlayExplicit    string `xml:"googleplay:explicit,omitempty"`
+	GooglePlayBlock       string `xml:"googleplay:block,omitempty"`
+
+	// https://github.com/Podcastindex-org/podcast-namespace/blob/main/docs/1.0.md
+	PGUID     *PodcastGUID
+	PLocked   *PodcastLocked
+	PFunding  []*PodcastFunding
+	PPersons  []*PodcastPerson
+	PLocation *PodcastLocation
+	PValue    *PodcastValue
+
+	// https://tools.ietf.org/html/rfc5005
+	FHArchive *FHArchive
+
+	// Extensions are the elements of other namespaces, see
+	// ExtensionElement.
+	Extensions []*ExtensionElement
+
+	// DurationFormat is the style the itunes:duration of the Items is
+	// written in.
+	DurationFormat DurationFormat `xml:"-"`
+
+	// HTMLPolicy, when set, sanitizes the descriptions of the Podcast and
+	// its Items as they are encoded.
+	HTMLPolicy *htmlpolicy.Policy `xml:"-"`
 
 	Items []*Item
 
 	encode func(w io.Writer, o interface{}) error
+
+	// descriptionHTML is the description given to AddDescription, before
+	// it was flattened to descriptionText.
+	descriptionHTML, descriptionText string
 }
 
 // New instantiates a Podcast with required parameters.
@@ -79,13 +114,20 @@ type Podcast struct {
 // to the expected proper formats.
 func New(title, link string, description Description,
 	pubDate, lastBuildDate *time.Time) Podcast {
-	return Podcast{
+	p := Podcast{
 		Title:       GenerateFeedString(title),
 		Link:        link,
 		Description: &description,
 		// setup dependency (could inject later)
 		encode: encoder,
 	}
+	if pubDate != nil {
+		p.SetPubDate(*pubDate)
+	}
+	if lastBuildDate != nil {
+		p.SetLastBuildDate(*lastBuildDate)
+	}
+	return p
 }
 
 func (p *Podcast) AddTitle(title string) {
@@ -143,75 +185,7 @@ func (p *Podcast) AddAtomLink(href string) {
 //
 // Note that Apple iTunes has a specific list of categories that only can be
 // used and will invalidate the feed if deviated from the list.  That list is
-// as follows.
-//
-//   - Arts
-//   - Design
-//   - Fashion & Beauty
-//   - Food
-//   - Literature
-//   - Performing Arts
-//   - Visual Arts
-//   - Business
-//   - Business News
-//   - Careers
-//   - Investing
-//   - Management & Marketing
-//   - Shopping
-//   - Comedy
-//   - Education
-//   - Education Technology
-//   - Higher Education
-//   - K-12
-//   - Language Courses
-//   - Training
-//   - Games & Hobbies
-//   - Automotive
-//   - Aviation
-//   - Hobbies
-//   - Other Games
-//   - Video Games
-//   - Government & Organizations
-//   - Local
-//   - National
-//   - Non-Profit
-//   - Regional
-//   - Health
-//   - Alternative Health
-//   - Fitness & Nutrition
-//   - Self-Help
-//   - Sexuality
-//   - Kids & Family
-//   - Music
-//   - News & Politics
-//   - Religion & Spirituality
-//   - Buddhism
-//   - Christianity
-//   - Hinduism
-//   - Islam
-//   - Judaism
-//   - Other
-//   - Spirituality
-//   - Science & Medicine
-//   - Medicine
-//   - Natural Sciences
-//   - Social Sciences
-//   - Society & Culture
-//   - History
-//   - Personal Journals
-//   - Philosophy
-//   - Places & Travel
-//   - Sports & Recreation
-//   - Amateur
-//   - College & High School
-//   - Outdoor
-//   - Professional
-//   - Technology
-//   - Gadgets
-//   - Podcasting
-//   - Software How-To
-//   - Tech News
-//   - TV & Film
+// in the taxonomy package, see SetCategories.
 func (p *Podcast) AddCategory(category string, subCategories []string) {
 	if len(category) == 0 {
 		return
@@ -229,84 +203,159 @@ func (p *Podcast) AddCategory(category string, subCategories []string) {
 	p.ICategories = append(p.ICategories, &icat)
 }
 
-func (p *Podcast) AddCopyright(copyright string) {
-	if len(copyright) == 0 {
-		return
+// AddCloud adds the cloud to be notified of updates to the Podcast.  The
+// protocol is one of CloudXMLRPC, CloudSOAP or CloudHTTPPost.
+func (p *Podcast) AddCloud(domain string, port int, path, registerProcedure, protocol string) error {
+	c := &Cloud{
+		Domain:            domain,
+		Port:              port,
+		Path:              path,
+		RegisterProcedure: registerProcedure,
+		Protocol:          protocol,
 	}
-	p.Copyright = GenerateFeedString(copyright)
+	if !validCloud(c) {
+		return errors.New("podcast.AddCloud: domain, path, a port and a known protocol are required")
+	}
+	p.Cloud = c
+	return nil
 }
 
-type podcastCategory struct {
-	Name            string
-	ParentCategory  string
-	ChildCategories []string
+// AddSkipHours adds the hours, from 0 to 23 in GMT, in which aggregators
+// may skip reading the feed.  Hours already added are skipped.
+func (p *Podcast) AddSkipHours(hours ...int) error {
+	for _, h := range hours {
+		if !validSkipHour(h) {
+			return errors.Errorf("podcast.AddSkipHours: hour %d is not between 0 and 23", h)
+		}
+	}
+	if p.SkipHours == nil {
+		p.SkipHours = &SkipHours{}
+	}
+	for _, h := range hours {
+		if !containsInt(p.SkipHours.Hours, h) {
+			p.SkipHours.Hours = append(p.SkipHours.Hours, h)
+		}
+	}
+	sort.Ints(p.SkipHours.Hours)
+	return nil
 }
 
-// Utility function to parse categories and divide them into
-func ParseCategories(categories []string) map[string][]string {
-	PODCAST_CATEGORIES := []podcastCategory{}
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Arts", ChildCategories: []string{"Books", "Design", "Fashion & Beauty", "Food", "Performing Arts", "Visual Arts"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Business", ChildCategories: []string{"Careers", "Entrepreneurship", "Investing", "Management", "Marketing", "Non-Profit"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Comedy", ChildCategories: []string{"Comedy Interviews", "Improv", "Stand-Up"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Education", ChildCategories: []string{"Courses", "How To", "Language Learning", "Self-Improvement"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Fiction", ChildCategories: []string{"Comedy Fiction", "Drama", "Science Fiction"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Government", ChildCategories: []string{}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "History", ChildCategories: []string{}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Health & Fitness", ChildCategories: []string{"Alternative Health", "Fitness", "Medicine", "Mental Health", "Nutrition", "Sexuality"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Kids & Family", ChildCategories: []string{"Education for Kids", "Parenting", "Pets & Animals", "Stories for Kids"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Leisure", ChildCategories: []string{"Animation & Manga", "Automotive", "Aviation", "Crafts", "Games", "Hobbies", "Home & Garden", "Video Games"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Music", ChildCategories: []string{"Music Commentary", "Music History", "Music Interviews"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "News", ChildCategories: []string{"Business News", "Daily News", "Entertainment News", "News Commentary", "Politics", "Sports News", "Tech News"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Religion & Spirituality", ChildCategories: []string{"Buddhism", "Christianity", "Hinduism", "Islam", "Judaism", "Religion", "Spirituality"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Science", ChildCategories: []string{"Astronomy", "Chemistry", "Earth Sciences", "Life Sciences", "Mathematics", "Natural Sciences", "Nature", "Physics", "Social Sciences"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Society & Culture", ChildCategories: []string{"Documentary", "Personal Journals", "Philosophy", "Places & Travel", "Relationships"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Sports", ChildCategories: []string{"Baseball", "Basketball", "Cricket", "Fantasy Sports", "Football", "Golf", "Hockey", "Rugby", "Running", "Soccer", "Swimming", "Tennis", "Volleyball", "Wilderness", "Wrestling"}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "Technology", ChildCategories: []string{}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "True Crime", ChildCategories: []string{}})
-	PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: "TV & Film", ChildCategories: []string{"After Shows", "Film History", "Film Interviews", "Film Reviews", "TV Reviews"}})
-
-	for _, pc := range PODCAST_CATEGORIES {
-		for _, child := range pc.ChildCategories {
-			PODCAST_CATEGORIES = append(PODCAST_CATEGORIES, podcastCategory{Name: child, ParentCategory: pc.Name})
+// AddSkipDays adds the days, such as "Saturday", on which aggregators may
+// skip reading the feed.  Days already added are skipped.
+func (p *Podcast) AddSkipDays(days ...string) error {
+	valid := make([]string, len(days))
+	for n, d := range days {
+		day, ok := skipDay(d)
+		if !ok {
+			return errors.Errorf("podcast.AddSkipDays: %q is not a day of the week", d)
 		}
+		valid[n] = day
 	}
-
-	var findPodcastCategory = func(category string) *podcastCategory {
-		for _, pc := range PODCAST_CATEGORIES {
-			if &pc != nil && pc.Name == category {
-				return &pc
-			}
+	if p.SkipDays == nil {
+		p.SkipDays = &SkipDays{}
+	}
+	for _, d := range valid {
+		if !containsString(p.SkipDays.Days, d) {
+			p.SkipDays.Days = append(p.SkipDays.Days, d)
 		}
-		return nil
 	}
+	return nil
+}
 
-	parsedCategories := make(map[string][]string)
+// SetCategories replaces the itunes:category of the Podcast with the
+// categories, nesting each subcategory under its parent.  Parents are
+// written in the order they are first seen, and a subcategory brings its
+// parent along.
+//
+// The categories are left unchanged when any of them is not in the
+// taxonomy.
+func (p *Podcast) SetCategories(categories ...taxonomy.Category) error {
+	names := make([]string, len(categories))
+	for n, c := range categories {
+		if !c.Valid() {
+			return errors.Errorf("podcast.SetCategories: unknown category %q", c)
+		}
+		names[n] = c.String()
+	}
+	order, children, err := taxonomy.Parse(names)
+	if err != nil {
+		return errors.Wrap(err, "podcast.SetCategories")
+	}
 
-	for _, category := range categories {
-		pc := findPodcastCategory(category)
-		if &pc != nil && pc != nil && pc.ParentCategory != "" {
-			parentCat := GenerateFeedString(pc.ParentCategory)
-			parsedCategories[parentCat] = append(parsedCategories[parentCat], strings.Replace(pc.Name, "&", "&amp;", -1))
-		} else {
-			cat := GenerateFeedString(pc.Name)
-			parsedCategories[cat] = []string{}
+	p.ICategories = nil
+	for _, parent := range order {
+		icat := &ICategory{Text: parent.String()}
+		for _, sub := range children[parent] {
+			icat.ICategories = append(icat.ICategories, &ICategory{Text: sub.String()})
 		}
+		p.ICategories = append(p.ICategories, icat)
+	}
+	return nil
+}
+
+func (p *Podcast) AddCopyright(copyright string) {
+	if len(copyright) == 0 {
+		return
 	}
+	p.Copyright = GenerateFeedString(copyright)
+}
 
+// ParseCategories maps the top-level categories of the taxonomy package
+// to their subcategories, which are in the order they are first seen.  A
+// subcategory brings its parent along.  As a map, the result has no order
+// of the top-level categories.
+//
+// Names that are not in the taxonomy are skipped without an error; use
+// taxonomy.Parse to have them reported and the parents in order, and
+// SetCategories to set them on a Podcast.
+func ParseCategories(categories []string) map[string][]string {
+	parsedCategories := make(map[string][]string)
+	for _, name := range categories {
+		c, err := taxonomy.Lookup(name)
+		if err != nil {
+			continue
+		}
+		parent := c.Parent()
+		if len(parent) == 0 {
+			parent = c
+		}
+		subs, ok := parsedCategories[parent.String()]
+		if !ok {
+			subs = []string{}
+		}
+		if c != parent && !containsString(subs, c.String()) {
+			subs = append(subs, c.String())
+		}
+		parsedCategories[parent.String()] = subs
+	}
 	return parsedCategories
 }
 
+// AddDescription adds the description of the Podcast, flattened to plain
+// text.  When an HTMLPolicy is set, the HTML given here is written
+// sanitized with it instead.  The itunes:summary is always plain text.
 func (p *Podcast) AddDescription(description Description) {
 	if len(description.Text) <= 0 {
 		return
 	}
 
-	p.Description = &Description{
-		Text: html2text.HTML2Text(description.Text),
-	}
-	p.ISummary = &ISummary{
-		Text: html2text.HTML2Text(description.Text),
+	text := html2text.HTML2Text(description.Text)
+	p.Description = &Description{Text: text}
+	p.ISummary = &ISummary{Text: text}
+	p.descriptionHTML, p.descriptionText = description.Text, text
+}
+
+// AddFunding adds a podcast:funding link where listeners can support the
+// show.  Calling this method multiple times will APPEND the link.
+func (p *Podcast) AddFunding(url, text string) {
+	if len(url) == 0 {
+		return
 	}
+
+	p.PFunding = append(p.PFunding, &PodcastFunding{
+		URL:  url,
+		Text: GenerateFeedString(text),
+	})
 }
 
 func (p *Podcast) AddGenerator(generator string) {
@@ -317,12 +366,31 @@ func (p *Podcast) AddGenerator(generator string) {
 	p.Generator = generator
 }
 
+// AddLastBuildDate sets the lastBuildDate from an RFC 2822 formatted
+// string, normalised to RFC 1123Z.  Other strings are set as they are.
 func (p *Podcast) AddLastBuildDate(datetime string) {
 	if len(datetime) == 0 {
 		return
 	}
+	t, err := parsePubDate(datetime)
+	if err != nil {
+		p.LastBuildDate = datetime
+		return
+	}
+
+	p.SetLastBuildDate(t)
+}
+
+// SetLastBuildDate sets the lastBuildDate, formatted as RFC 1123Z.
+//
+// Note that AddItem moves the lastBuildDate forward to the PubDate of
+// the newest Item.
+func (p *Podcast) SetLastBuildDate(t time.Time) {
+	if t.IsZero() {
+		return
+	}
 
-	p.LastBuildDate = datetime
+	p.LastBuildDate = t.Format(time.RFC1123Z)
 }
 
 // Podcast Language Codes.
@@ -448,6 +516,47 @@ func (p *Podcast) AddLanguage(language string) {
 	p.Language = GenerateFeedString(language)
 }
 
+// AddLocation adds the podcast:location the show is about.
+//
+// geo is a "geo:" URI (RFC 5870) and osm an OpenStreetMap identifier,
+// both optional.
+func (p *Podcast) AddLocation(name, geo, osm string) {
+	if len(name) == 0 {
+		return
+	}
+
+	p.PLocation = &PodcastLocation{
+		Name: GenerateFeedString(name),
+		Geo:  geo,
+		OSM:  osm,
+	}
+}
+
+// AddLocked sets podcast:locked, telling other platforms whether they
+// may import this feed.  owner is the email address that can unlock it.
+func (p *Podcast) AddLocked(locked bool, owner string) {
+	value := "no"
+	if locked {
+		value = "yes"
+	}
+
+	p.PLocked = &PodcastLocked{
+		Owner: owner,
+		Value: value,
+	}
+}
+
+// AddValue sets the podcast:value block of the show, after checking it
+// with PodcastValue.Validate.  Build it with NewValue, AddRecipient and
+// AddFee.
+func (p *Podcast) AddValue(value PodcastValue) error {
+	if err := value.Validate(); err != nil {
+		return errors.Wrap(err, "podcast.AddValue: invalid value")
+	}
+	p.PValue = &value
+	return nil
+}
+
 func (p *Podcast) AddParentalAdvisory(parentalAdvisory string) {
 	if parentalAdvisory == ParentalAdvisoryExplicit {
 		p.IExplicit = "yes"
@@ -502,8 +611,12 @@ func (p *Podcast) AddImage(url string) {
 //   - Description
 //   - Enclosure (HREF, Type and Length all required)
 //
+// When Enclosure is not set, it is picked from the PAlternateEnclosures
+// for legacy clients, see Item.AddAlternateEnclosure.
+//
 // The following fields are always overwritten (don't set them):
 //
+//   - LastBuildDate, when the Item is newer
 //   - GUID
 //   - PubDateFormatted
 //   - AuthorFormatted
@@ -520,6 +633,9 @@ func (p *Podcast) AddImage(url string) {
 //     https://help.apple.com/itc/podcasts_connect/#/itcb54353390
 func (p *Podcast) AddItem(i Item) (int, error) {
 	// initial guards for required fields
+	if i.Enclosure == nil {
+		i.Enclosure = i.primaryEnclosure()
+	}
 	if len(i.Title) == 0 {
 		return len(p.Items), errors.New("Title and Description are required")
 	}
@@ -528,7 +644,7 @@ func (p *Podcast) AddItem(i Item) (int, error) {
 			return len(p.Items),
 				errors.New(i.Title + ": Enclosure.URL is required")
 		}
-		if i.Enclosure.TypeFormatted == enclosureDefault {
+		if i.Enclosure.TypeFormatted == enclosureDefault || !i.Enclosure.Type.IsRegistered() {
 			return len(p.Items),
 				errors.New(i.Title + ": Enclosure.Type is required")
 		}
@@ -580,10 +696,121 @@ func (p *Podcast) AddItem(i Item) (int, error) {
 		}
 	}
 
+	// the newest episode is the last change to the feed
+	if pubDate, err := parsePubDate(i.PubDate); err == nil {
+		lastBuildDate, err := parsePubDate(p.LastBuildDate)
+		if err != nil || pubDate.After(lastBuildDate) {
+			p.SetLastBuildDate(pubDate)
+		}
+	}
+
 	p.Items = append(p.Items, &i)
 	return len(p.Items), nil
 }
 
+// AddGooglePlayAuthor adds the googleplay:author, falling back to the
+// iTunes author when empty.
+func (p *Podcast) AddGooglePlayAuthor(author string) {
+	if len(author) == 0 {
+		p.GooglePlayAuthor = p.IAuthor
+		return
+	}
+
+	p.GooglePlayAuthor = GenerateFeedString(author)
+}
+
+// AddGooglePlayBlock hides the show from Google Podcasts when block is
+// "hide", falling back to the iTunes block when empty.
+func (p *Podcast) AddGooglePlayBlock(block string) {
+	switch {
+	case block == "hide":
+		p.GooglePlayBlock = "Yes"
+	case len(block) == 0 && len(p.IBlock) != 0:
+		p.GooglePlayBlock = p.IBlock
+	default:
+		p.GooglePlayBlock = "No"
+	}
+}
+
+// AddGooglePlayCategory adds the googleplay:category to the Podcast.
+// Calling this method multiple times will APPEND the category.
+//
+// When category is empty, the iTunes categories are copied instead.
+func (p *Podcast) AddGooglePlayCategory(category string, subCategories []string) {
+	if len(category) == 0 {
+		for _, icat := range p.ICategories {
+			p.GooglePlayCategories = append(p.GooglePlayCategories, googlePlayCategory(icat))
+		}
+		return
+	}
+
+	gcat := GooglePlayCategory{Text: category}
+	for _, c := range subCategories {
+		if len(c) == 0 {
+			continue
+		}
+		gcat.GooglePlayCategories = append(gcat.GooglePlayCategories, &GooglePlayCategory{Text: c})
+	}
+
+	p.GooglePlayCategories = append(p.GooglePlayCategories, &gcat)
+}
+
+// AddGooglePlayDescription adds the plain text googleplay:description,
+// falling back to the iTunes summary or description when empty.
+//
+// Limit: 4000 characters
+func (p *Podcast) AddGooglePlayDescription(description string) {
+	switch {
+	case len(description) != 0:
+	case p.ISummary != nil:
+		description = p.ISummary.Text
+	case p.Description != nil:
+		description = p.Description.Text
+	}
+
+	p.GooglePlayDescription = truncateRunes(html2text.HTML2Text(description), 4000)
+}
+
+// AddGooglePlayExplicit sets googleplay:explicit from the parental
+// advisory, falling back to the iTunes explicit flag when it is neither
+// ParentalAdvisoryExplicit nor ParentalAdvisoryClean.
+func (p *Podcast) AddGooglePlayExplicit(parentalAdvisory string) {
+	switch parentalAdvisory {
+	case ParentalAdvisoryExplicit:
+		p.GooglePlayExplicit = "yes"
+	case ParentalAdvisoryClean:
+		p.GooglePlayExplicit = "no"
+	default:
+		p.GooglePlayExplicit = p.IExplicit
+	}
+}
+
+// AddGooglePlayImage adds the googleplay:image, falling back to the
+// iTunes image when empty.
+func (p *Podcast) AddGooglePlayImage(url string) {
+	if len(url) == 0 && p.IImage != nil {
+		url = p.IImage.HREF
+	}
+	if len(url) == 0 {
+		return
+	}
+
+	p.GooglePlayImage = &GooglePlayImage{HREF: url}
+}
+
+// AddGooglePlayOwner adds the googleplay:owner email, falling back to the
+// iTunes owner email when empty.
+func (p *Podcast) AddGooglePlayOwner(email string) {
+	if len(email) == 0 {
+		if p.IOwner != nil {
+			p.GooglePlayOwner = p.IOwner.Email
+		}
+		return
+	}
+
+	p.GooglePlayOwner = GenerateFeedString(email)
+}
+
 func (p *Podcast) AddItunesBlock(block string) {
 	if block == "hide" {
 		p.IBlock = "Yes"
@@ -608,10 +835,49 @@ func (p *Podcast) AddItunesTitle(title string) {
 	p.ITitle = GenerateFeedString(title)
 }
 
+// AddItunesType adds the itunes:type of the show, either ShowTypeEpisodic
+// or ShowTypeSerial.  Other types are ignored.
 func (p *Podcast) AddItunesType(showType string) {
+	if !validShowType(showType) {
+		return
+	}
 	p.IType = showType
 }
 
+// OrderEpisodes sorts the Items by season and episode number and returns
+// the SerialProfile Findings of the sorted Items.
+//
+// A serial show is sorted oldest first, as it is meant to be listened
+// to: within a season, trailers come first, then the numbered episodes
+// and then the unnumbered ones in their current order.  An episodic show
+// is sorted in the reverse order, newest first.  When fillOrder is set,
+// the itunes:order of every Item is set to its 1-based position.
+//
+// The Items are left unchanged when itunes:type is neither episodic nor
+// serial.
+func (p *Podcast) OrderEpisodes(fillOrder bool) (Findings, error) {
+	if !validShowType(p.IType) {
+		return nil, errors.Errorf("podcast.OrderEpisodes: itunes:type %q is neither episodic nor serial", p.IType)
+	}
+
+	keys := make(map[*Item]episodeKey, len(p.Items))
+	for _, i := range p.Items {
+		keys[i] = newEpisodeKey(i)
+	}
+	sort.SliceStable(p.Items, func(a, b int) bool {
+		if p.IType == ShowTypeEpisodic {
+			return keys[p.Items[b]].less(keys[p.Items[a]])
+		}
+		return keys[p.Items[a]].less(keys[p.Items[b]])
+	})
+	if fillOrder {
+		for n, i := range p.Items {
+			i.IOrder = strconv.Itoa(n + 1)
+		}
+	}
+	return p.Validate(SerialProfile), nil
+}
+
 func (p *Podcast) AddLink(link string) {
 	if len(link) == 0 {
 		return
@@ -635,13 +901,85 @@ func (p *Podcast) AddOwner(name, email string) {
 	}
 }
 
-func (p *Podcast) AddPubDate(datetime string) {
+// SetHTMLPolicy sets the policy the description of the Podcast and the
+// description and content:encoded of the Items are sanitized with as they
+// are encoded, such as htmlpolicy.Apple.  The Podcast and its Items are
+// left as they are.
+func (p *Podcast) SetHTMLPolicy(policy htmlpolicy.Policy) {
+	p.HTMLPolicy = &policy
+}
+
+// SetDurationFormat sets the style the itunes:duration of the Items is
+// written in.  The Items themselves are left as they are.
+func (p *Podcast) SetDurationFormat(format DurationFormat) {
+	p.DurationFormat = format
+}
+
+// AddRating adds the PICS rating of the Podcast.
+func (p *Podcast) AddRating(rating string) {
+	if len(rating) == 0 {
+		return
+	}
+	p.Rating = rating
+}
+
+// AddPagingLink adds an atom:link to another document of a paged feed,
+// such as RelFirst, RelNext, RelPrev or RelLast.
+func (p *Podcast) AddPagingLink(rel, href string) {
+	if len(rel) == 0 || len(href) == 0 {
+		return
+	}
+	p.PagingLinks = append(p.PagingLinks, &AtomLink{
+		HREF: href,
+		Rel:  rel,
+		Type: "application/rss+xml",
+	})
+}
+
+// AddPerson adds a podcast:person, such as a host, to the show.
+// Calling this method multiple times will APPEND the person.
+func (p *Podcast) AddPerson(person PodcastPerson) {
+	if len(person.Name) == 0 {
+		return
+	}
+
+	person.Name = GenerateFeedString(person.Name)
+	p.PPersons = append(p.PPersons, &person)
+}
+
+// AddPodcastGUID sets the podcast:guid of the show.
+//
+// Use GeneratePodcastGUID to derive it from the feed URL.
+func (p *Podcast) AddPodcastGUID(guid string) {
+	if len(guid) == 0 {
+		return
+	}
 
+	p.PGUID = &PodcastGUID{Value: guid}
+}
+
+// AddPubDate sets the pubDate from an RFC 2822 formatted string,
+// normalised to RFC 1123Z.  Other strings are set as they are.
+func (p *Podcast) AddPubDate(datetime string) {
 	if len(datetime) == 0 {
 		return
 	}
+	t, err := parsePubDate(datetime)
+	if err != nil {
+		p.PubDate = datetime
+		return
+	}
+
+	p.SetPubDate(t)
+}
+
+// SetPubDate sets the pubDate, formatted as RFC 1123Z.
+func (p *Podcast) SetPubDate(t time.Time) {
+	if t.IsZero() {
+		return
+	}
 
-	p.PubDate = datetime
+	p.PubDate = t.Format(time.RFC1123Z)
 }
 
 // AddSubTitle adds the iTunes subtitle that is displayed with the title
@@ -688,6 +1026,9 @@ func (p *Podcast) AddSummary(summary string) {
 // }
 
 // Encode writes the bytes to the io.Writer stream in RSS 2.0 specification.
+//
+// The text of the Podcast and its Items is sanitized as GenerateFeedString
+// does on a copy, leaving the Podcast itself unchanged.
 func (p *Podcast) Encode(w io.Writer) error {
 	if _, err := w.Write([]byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")); err != nil {
 		return errors.Wrap(err, "podcast.Encode: w.Write return error")
@@ -698,14 +1039,24 @@ func (p *Podcast) Encode(w io.Writer) error {
 	// 	atomLink = "http://www.w3.org/2005/Atom"
 	// }
 	wrapped := PodcastWrapper{
-		ITUNESNS:  ITUNESNS,
-		CONTENT:   CONTENT,
-		PODCASTNS: PODCASTNS,
-		ATOMNS:    ATOMNS,
-		Version:   "2.0",
-		Channel:   p,
+		ITUNESNS:     ITUNESNS,
+		CONTENT:      CONTENT,
+		PODCASTNS:    PODCASTNS,
+		ATOMNS:       ATOMNS,
+		GOOGLEPLAYNS: GOOGLEPLAYNS,
+		FHNS:         p.fhNS(),
+		Version:      "2.0",
+		Channel:      p.encodable(),
 	}
-	return p.encode(w, wrapped)
+
+	b := new(bytes.Buffer)
+	if err := p.encode(b, wrapped); err != nil {
+		return err
+	}
+	if _, err := w.Write(escapeSymbols(b.Bytes())); err != nil {
+		return errors.Wrap(err, "podcast.Encode: w.Write return error")
+	}
+	return nil
 }
 
 // String encodes the Podcast state to a string.
@@ -728,23 +1079,27 @@ func (p *Podcast) String() string {
 // }
 
 type PodcastWrapper struct {
-	XMLName   xml.Name `xml:"rss"`
-	Version   string   `xml:"version,attr"`
-	ATOMNS    string   `xml:"xmlns:atom,attr,omitempty"`
-	PODCASTNS string   `xml:"xmlns:podcast,attr,omitempty"`
-	ITUNESNS  string   `xml:"xmlns:itunes,attr"`
-	CONTENT   string   `xml:"xmlns:content,attr"`
-	Channel   *Podcast
+	XMLName      xml.Name `xml:"rss"`
+	Version      string   `xml:"version,attr"`
+	ATOMNS       string   `xml:"xmlns:atom,attr,omitempty"`
+	PODCASTNS    string   `xml:"xmlns:podcast,attr,omitempty"`
+	ITUNESNS     string   `xml:"xmlns:itunes,attr"`
+	CONTENT      string   `xml:"xmlns:content,attr"`
+	GOOGLEPLAYNS string   `xml:"xmlns:googleplay,attr,omitempty"`
+	FHNS         string   `xml:"xmlns:fh,attr,omitempty"`
+	Channel      *Podcast
 }
 
 func NewWrapper(p *Podcast) PodcastWrapper {
 	return PodcastWrapper{
-		ATOMNS:    ATOMNS,
-		ITUNESNS:  ITUNESNS,
-		PODCASTNS: PODCASTNS,
-		CONTENT:   CONTENT,
-		Version:   "2.0",
-		Channel:   p,
+		ATOMNS:       ATOMNS,
+		ITUNESNS:     ITUNESNS,
+		PODCASTNS:    PODCASTNS,
+		CONTENT:      CONTENT,
+		GOOGLEPLAYNS: GOOGLEPLAYNS,
+		FHNS:         p.fhNS(),
+		Version:      "2.0",
+		Channel:      p,
 	}
 }
 
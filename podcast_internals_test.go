@@ -60,3 +60,24 @@ func TestParseDuration(t *testing.T) {
 	assert.Equal(t, "10:01:00", parseDuration(36060))
 	assert.Equal(t, "10:01:03", parseDuration(36063))
 }
+
+func TestDurationSeconds(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []int64{0, 40, 121, 3599, 3600, 3663, 36063} {
+		d, err := durationSeconds(parseDuration(s))
+		assert.NoError(t, err)
+		assert.Equal(t, s, d)
+	}
+
+	d, err := durationSeconds("1800")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1800, d)
+
+	_, err = durationSeconds("1:2:3:4")
+	assert.Error(t, err)
+	_, err = durationSeconds("")
+	assert.Error(t, err)
+	_, err = durationSeconds("1:-1")
+	assert.Error(t, err)
+}
@@ -1,11 +1,14 @@
 package podcast_test
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 	"time"
 
 	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/georgboe/rss-feed-generator/htmlpolicy"
+	"github.com/georgboe/rss-feed-generator/taxonomy"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,6 +31,8 @@ func TestNewNonNils(t *testing.T) {
 	assert.EqualValues(t, ti, p.Title)
 	assert.EqualValues(t, l, p.Link)
 	assert.EqualValues(t, d.Text, p.Description.Text)
+	assert.EqualValues(t, "Wed, 01 Feb 2017 08:21:52 +0000", p.PubDate)
+	assert.EqualValues(t, "Mon, 06 Feb 2017 08:21:52 +0000", p.LastBuildDate)
 }
 
 func TestNewNils(t *testing.T) {
@@ -43,6 +48,8 @@ func TestNewNils(t *testing.T) {
 	assert.EqualValues(t, ti, p.Title)
 	assert.EqualValues(t, l, p.Link)
 	assert.EqualValues(t, d.Text, p.Description.Text)
+	assert.Len(t, p.PubDate, 0)
+	assert.Len(t, p.LastBuildDate, 0)
 }
 
 func TestAddAuthorEmpty(t *testing.T) {
@@ -70,7 +77,7 @@ func TestAddAuthor(t *testing.T) {
 
 	// assert
 	// assert.Len(t, p.ManagingEditor, 0)
-	assert.Equal(t, p.IAuthor, "Joe, Lisa, Aaron&apos;s Woods")
+	assert.Equal(t, p.IAuthor, "Joe, Lisa, Aaron's Woods")
 }
 
 func TestAddCopyright(t *testing.T) {
@@ -83,7 +90,7 @@ func TestAddCopyright(t *testing.T) {
 	p.AddCopyright("inserted © text")
 
 	// assert
-	assert.Equal(t, p.Copyright, "inserted &#xA9; text")
+	assert.Equal(t, p.Copyright, "inserted © text")
 }
 
 func TestAddCopyrightEmpty(t *testing.T) {
@@ -195,11 +202,11 @@ func TestParseCategories(t *testing.T) {
 	out := podcast.ParseCategories([]string{"Arts", "Books", "Religion & Spirituality", "Christianity", "Buddhism", "Sports", "Health & Fitness", "Documentary"})
 
 	expected := map[string][]string{
-		"Arts":                        []string{"Books"},
-		"Religion &amp; Spirituality": []string{"Christianity", "Buddhism"},
-		"Sports":                      []string{},
-		"Health &amp; Fitness":        []string{},
-		"Society &amp; Culture":       []string{"Documentary"},
+		"Arts":                    []string{"Books"},
+		"Religion & Spirituality": []string{"Christianity", "Buddhism"},
+		"Sports":                  []string{},
+		"Health & Fitness":        []string{},
+		"Society & Culture":       []string{"Documentary"},
 	}
 
 	assert.EqualValues(t, expected, out)
@@ -211,12 +218,62 @@ func TestParseCategoriesChildCatEmpty(t *testing.T) {
 	out := podcast.ParseCategories([]string{"Health & Fitness"})
 
 	expected := map[string][]string{
-		"Health &amp; Fitness": []string{},
+		"Health & Fitness": []string{},
+	}
+
+	assert.EqualValues(t, expected, out)
+}
+
+func TestParseCategoriesUnknown(t *testing.T) {
+	t.Parallel()
+
+	out := podcast.ParseCategories([]string{"Cats", "kids and family", "Parenting", "Parenting"})
+
+	expected := map[string][]string{
+		"Kids & Family": []string{"Parenting"},
 	}
 
 	assert.EqualValues(t, expected, out)
 }
 
+func TestSetCategories(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddCategory("Old", nil)
+
+	// act
+	err := p.SetCategories(taxonomy.NewsTechNews, taxonomy.Technology, taxonomy.NewsPolitics, taxonomy.News)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, p.ICategories, 2)
+	assert.Equal(t, "News", p.ICategories[0].Text)
+	assert.Len(t, p.ICategories[0].ICategories, 2)
+	assert.Equal(t, "Tech News", p.ICategories[0].ICategories[0].Text)
+	assert.Equal(t, "Politics", p.ICategories[0].ICategories[1].Text)
+	assert.Equal(t, "Technology", p.ICategories[1].Text)
+	assert.Len(t, p.ICategories[1].ICategories, 0)
+	assert.Contains(t, p.String(), `<itunes:category text="News">
+      <itunes:category text="Tech News"></itunes:category>`)
+}
+
+func TestSetCategoriesUnknown(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddCategory("Arts", nil)
+
+	// act
+	err := p.SetCategories(taxonomy.Arts, taxonomy.Category("Games & Hobbies"))
+
+	// assert
+	assert.Error(t, err)
+	assert.Len(t, p.ICategories, 1)
+}
+
 func TestAddPodcastGeneratorEmpty(t *testing.T) {
 	p := podcast.Podcast{}
 
@@ -253,6 +310,94 @@ func TestAddPodcastLastBuildDate(t *testing.T) {
 	assert.Equal(t, p.LastBuildDate, "Sun, 14 Mar 2021 18:34:05 +0000")
 }
 
+func TestAddPodcastLastBuildDateInvalid(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.Podcast{}
+
+	p.AddLastBuildDate("14/03/2021")
+
+	assert.Equal(t, "14/03/2021", p.LastBuildDate)
+}
+
+func TestSetPodcastLastBuildDate(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.Podcast{}
+
+	p.SetLastBuildDate(time.Date(2021, time.March, 14, 18, 34, 5, 0, time.FixedZone("CET", 3600)))
+
+	assert.Equal(t, "Sun, 14 Mar 2021 18:34:05 +0100", p.LastBuildDate)
+}
+
+func TestSetPodcastPubDateZero(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.Podcast{}
+
+	p.SetPubDate(time.Time{})
+
+	assert.Len(t, p.PubDate, 0)
+}
+
+func TestAddPodcastPubDateNormalized(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.Podcast{}
+
+	p.AddPubDate("Sun, 14 Mar 2021 18:34:05 GMT")
+
+	assert.Equal(t, "Sun, 14 Mar 2021 18:34:05 +0000", p.PubDate)
+}
+
+func TestAddPodcastPubDateRFC2822(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Fri, 5 Mar 2021 10:00:00 +0000", "Fri, 05 Mar 2021 10:00:00 +0000"},
+		{"5 Mar 2021 10:00:00 +0000", "Fri, 05 Mar 2021 10:00:00 +0000"},
+		{"Fri, 05 Mar 2021 10:00 +0000", "Fri, 05 Mar 2021 10:00:00 +0000"},
+		{"Fri, 05 Mar 2021 10:00:00 EST", "Fri, 05 Mar 2021 10:00:00 -0500"},
+		{"Fri,  5 Mar 2021 10:00:00 UT", "Fri, 05 Mar 2021 10:00:00 +0000"},
+		{"fri, 05 Mar 2021 10:00:00 +0000", "Fri, 05 Mar 2021 10:00:00 +0000"},
+		{"Mon, 05 Mar 2021 10:00:00 +0000", "Mon, 05 Mar 2021 10:00:00 +0000"},
+		{"Fri, 05 Mar 2021 10:00:00 XYZ", "Fri, 05 Mar 2021 10:00:00 XYZ"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			p := podcast.Podcast{}
+
+			p.AddPubDate(tt.in)
+
+			assert.Equal(t, tt.want, p.PubDate)
+		})
+	}
+}
+
+func TestAddItemDerivesLastBuildDate(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, &createdDate, &createdDate)
+	older := podcast.Item{Title: "older", Link: "http://example.com/1"}
+	older.SetPubDate(createdDate.AddDate(0, 0, -1))
+	newer := podcast.Item{Title: "newer", Link: "http://example.com/2"}
+	newer.SetPubDate(updatedDate)
+
+	// act
+	_, _ = p.AddItem(newer)
+	_, _ = p.AddItem(older)
+
+	// assert
+	assert.Equal(t, "Mon, 06 Feb 2017 08:21:52 +0000", p.LastBuildDate)
+}
+
 func TestAddPodcastPubDateEmpty(t *testing.T) {
 	t.Parallel()
 
@@ -782,7 +927,7 @@ func TestAddPodcastTitle(t *testing.T) {
 	p := podcast.Podcast{}
 	p.AddTitle("Podpal™, Inc. 20")
 
-	assert.Equal(t, p.Title, "Podpal&#x2122;, Inc. 20")
+	assert.Equal(t, p.Title, "Podpal™, Inc. 20")
 }
 
 func TestEncodeWriterError(t *testing.T) {
@@ -798,3 +943,212 @@ func TestEncodeWriterError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "w.Write return error")
 }
+
+func TestAddFundingEmpty(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	p.AddFunding("", "Support the show")
+
+	assert.Len(t, p.PFunding, 0)
+}
+
+func TestAddFunding(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	p.AddFunding("https://example.com/donate", "Donate")
+	p.AddFunding("https://example.com/patreon", "Patreon")
+
+	assert.Len(t, p.PFunding, 2)
+	assert.Equal(t, "https://example.com/patreon", p.PFunding[1].URL)
+}
+
+func TestAddLocked(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	p.AddLocked(false, "")
+
+	assert.Equal(t, "no", p.PLocked.Value)
+	assert.Empty(t, p.PLocked.Owner)
+}
+
+func TestAddPodcastGUIDEmpty(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	p.AddPodcastGUID("")
+
+	assert.Nil(t, p.PGUID)
+}
+
+func TestAddPersonEmpty(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	p.AddPerson(podcast.PodcastPerson{Role: "host"})
+
+	assert.Len(t, p.PPersons, 0)
+}
+
+func TestAddLocationEmpty(t *testing.T) {
+	t.Parallel()
+
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	p.AddLocation("", "geo:30.2672,97.7431", "")
+
+	assert.Nil(t, p.PLocation)
+}
+
+func newAlternateEnclosure(t *testing.T, mimeType, uri string, length int64, isDefault bool) podcast.PodcastAlternateEnclosure {
+	t.Helper()
+	ae := podcast.PodcastAlternateEnclosure{Type: mimeType, Length: length, Default: isDefault}
+	ae.AddSource(uri, "")
+	return ae
+}
+
+func TestAddItemPrimaryEnclosureDefault(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	i := podcast.Item{Title: "title", Description: &podcast.Description{Text: "desc"}}
+	i.AddAlternateEnclosure(newAlternateEnclosure(t, "audio/mpeg", "http://example.com/1.mp3", 2000, false))
+	i.AddAlternateEnclosure(newAlternateEnclosure(t, "audio/x-m4a", "http://example.com/1.m4a", 1000, true))
+
+	// act
+	_, err := p.AddItem(i)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "http://example.com/1.m4a", p.Items[0].Enclosure.URL)
+	assert.Equal(t, "audio/x-m4a", p.Items[0].Enclosure.TypeFormatted)
+	assert.Equal(t, "1000", p.Items[0].Enclosure.LengthFormatted)
+	assert.Len(t, p.Items[0].PAlternateEnclosures, 2)
+}
+
+func TestAddItemPrimaryEnclosureRegisteredType(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	i := podcast.Item{Title: "title", Description: &podcast.Description{Text: "desc"}}
+	i.AddAlternateEnclosure(newAlternateEnclosure(t, "video/x-unregistered", "http://example.com/1.unregistered", 0, true))
+	i.AddAlternateEnclosure(newAlternateEnclosure(t, "audio/mpeg", "http://example.com/1.mp3", 2000, false))
+
+	// act
+	_, err := p.AddItem(i)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "http://example.com/1.mp3", p.Items[0].Enclosure.URL)
+	assert.Equal(t, "audio/mpeg", p.Items[0].Enclosure.TypeFormatted)
+}
+
+func TestAddItemPrimaryEnclosureHTTPSource(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	i := podcast.Item{Title: "title", Description: &podcast.Description{Text: "desc"}}
+	ae := newAlternateEnclosure(t, "audio/x-m4a", "ipfs://QmdwGqd3d", 1000, true)
+	ae.AddSource("https://example.com/1.m4a", "")
+	i.AddAlternateEnclosure(ae)
+
+	// act
+	_, err := p.AddItem(i)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/1.m4a", p.Items[0].Enclosure.URL)
+}
+
+func TestAddItemPrimaryEnclosureNoHTTPSource(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	i := podcast.Item{Title: "title", Link: "http://example.com/1", Description: &podcast.Description{Text: "desc"}}
+	i.AddAlternateEnclosure(newAlternateEnclosure(t, "audio/x-m4a", "ipfs://QmdwGqd3d", 1000, true))
+
+	// act
+	_, err := p.AddItem(i)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Nil(t, p.Items[0].Enclosure)
+	assert.Len(t, p.Items[0].PAlternateEnclosures, 1)
+}
+
+func TestAddItemPrimaryEnclosureKept(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	i := podcast.Item{Title: "title", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 1)
+	i.AddAlternateEnclosure(newAlternateEnclosure(t, "audio/x-m4a", "http://example.com/1.m4a", 1000, true))
+
+	// act
+	_, err := p.AddItem(i)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "http://example.com/1.mp3", p.Items[0].Enclosure.URL)
+}
+
+func TestAddItemUnknownEnclosureType(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	i := podcast.Item{Title: "title", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/1.xyz", podcast.UnknownEnclosureType, "video/x-unknown", 1)
+
+	// act
+	added, err := p.AddItem(i)
+
+	// assert
+	assert.EqualValues(t, 0, added)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Enclosure.Type is required")
+}
+
+func TestSetHTMLPolicy(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddDescription(podcast.Description{Text: `<div>About <b>us</b></div><script>x()</script>`})
+	added := podcast.Item{Title: "added", Description: &podcast.Description{Text: `<p onclick="x()">one</p>`}}
+	added.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "", 1)
+	_, _ = p.AddItem(added)
+	appended := &podcast.Item{Title: "appended"}
+	appended.AddDescription(podcast.Description{Text: `<h1>Two</h1><a href="https://example.com/">link</a>`})
+	p.Items = append(p.Items, appended)
+	streamed := &podcast.Item{Title: "streamed", Description: &podcast.Description{Text: `<i>three</i><u>!</u>`}}
+
+	// act
+	p.SetHTMLPolicy(htmlpolicy.Apple)
+	out := p.String()
+	var b bytes.Buffer
+	err := p.EncodeStream(&b, podcast.ItemsFromSlice([]*podcast.Item{streamed}))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out, "<description><![CDATA[About <b>us</b>]]></description>")
+	assert.Contains(t, out, "<itunes:summary><![CDATA[About us]]></itunes:summary>")
+	assert.Contains(t, out, "<description><![CDATA[<p>one</p>]]></description>")
+	assert.Contains(t, out, "<description><![CDATA[Two\n<a href=\"https://example.com/\" rel=\"noopener noreferrer\">link</a>]]></description>")
+	assert.Contains(t, b.String(), "<description><![CDATA[<i>three</i>!]]></description>")
+	assert.Equal(t, "About us", p.Description.Text)
+	assert.Equal(t, `<p onclick="x()">one</p>`, p.Items[0].Description.Text)
+	assert.Equal(t, `<h1>Two</h1><a href="https://example.com/">link</a>`, appended.Description.Text)
+}
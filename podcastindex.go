@@ -0,0 +1,201 @@
+package podcast
+
+import (
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: https://github.com/Podcastindex-org/podcast-namespace/blob/main/docs/1.0.md
+//
+
+// podcastGUIDNamespace is the UUIDv5 namespace used to derive a podcast:guid
+// from the feed URL.
+var podcastGUIDNamespace = [16]byte{
+	0xea, 0xd4, 0xc2, 0x36, 0xbf, 0x58, 0x58, 0xc6,
+	0xa2, 0xc6, 0xa6, 0xb2, 0x8d, 0x12, 0x8c, 0xb6,
+}
+
+// PodcastGUID is the globally unique, permanent identifier of the show.
+type PodcastGUID struct {
+	XMLName xml.Name `xml:"podcast:guid"`
+	Value   string   `xml:",chardata"`
+}
+
+// PodcastLocked tells other podcast platforms whether they are allowed
+// to import this feed.
+type PodcastLocked struct {
+	XMLName xml.Name `xml:"podcast:locked"`
+	Owner   string   `xml:"owner,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+// PodcastFunding lists a donation or funding link for the show.
+type PodcastFunding struct {
+	XMLName xml.Name `xml:"podcast:funding"`
+	URL     string   `xml:"url,attr"`
+	Text    string   `xml:",chardata"`
+}
+
+// PodcastTranscript links to an external transcript or caption file.
+type PodcastTranscript struct {
+	XMLName  xml.Name `xml:"podcast:transcript"`
+	URL      string   `xml:"url,attr"`
+	Type     string   `xml:"type,attr"`
+	Language string   `xml:"language,attr,omitempty"`
+	Rel      string   `xml:"rel,attr,omitempty"`
+}
+
+// PodcastChapters links to an external chapters file.
+type PodcastChapters struct {
+	XMLName xml.Name `xml:"podcast:chapters"`
+	URL     string   `xml:"url,attr"`
+	Type    string   `xml:"type,attr"`
+}
+
+// PodcastPerson is a person of interest to the show or episode, such as a
+// host, guest or producer.
+type PodcastPerson struct {
+	XMLName xml.Name `xml:"podcast:person"`
+	Role    string   `xml:"role,attr,omitempty"`
+	Group   string   `xml:"group,attr,omitempty"`
+	Img     string   `xml:"img,attr,omitempty"`
+	Href    string   `xml:"href,attr,omitempty"`
+	Name    string   `xml:",chardata"`
+}
+
+// PodcastLocation is the location the show or episode is about.
+type PodcastLocation struct {
+	XMLName xml.Name `xml:"podcast:location"`
+	Geo     string   `xml:"geo,attr,omitempty"`
+	OSM     string   `xml:"osm,attr,omitempty"`
+	Name    string   `xml:",chardata"`
+}
+
+// PodcastSeason identifies the season an episode belongs to, with an
+// optional season name.
+type PodcastSeason struct {
+	XMLName xml.Name `xml:"podcast:season"`
+	Name    string   `xml:"name,attr,omitempty"`
+	Number  int64    `xml:",chardata"`
+}
+
+// PodcastEpisode identifies the episode number, which may be a decimal,
+// with an optional display value.
+type PodcastEpisode struct {
+	XMLName xml.Name `xml:"podcast:episode"`
+	Display string   `xml:"display,attr,omitempty"`
+	Number  float64  `xml:",chardata"`
+}
+
+// PodcastSoundbite is a short, shareable segment of an episode.
+//
+// StartTime and Duration are expressed in seconds.
+type PodcastSoundbite struct {
+	XMLName   xml.Name `xml:"podcast:soundbite"`
+	StartTime float64  `xml:"startTime,attr"`
+	Duration  float64  `xml:"duration,attr"`
+	Title     string   `xml:",chardata"`
+}
+
+// GeneratePodcastGUID derives the podcast:guid for a feed URL as specified
+// by the Podcasting 2.0 namespace: a UUIDv5 of the URL without its scheme
+// and trailing slashes.
+func GeneratePodcastGUID(feedURL string) string {
+	if i := strings.Index(feedURL, "://"); i >= 0 {
+		feedURL = feedURL[i+3:]
+	}
+	feedURL = strings.TrimRight(feedURL, "/")
+
+	h := sha1.New()
+	h.Write(podcastGUIDNamespace[:])
+	h.Write([]byte(feedURL))
+	u := h.Sum(nil)[:16]
+	u[6] = (u[6] & 0x0f) | 0x50
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// PodcastAlternateEnclosure is another variant of the episode media, such
+// as a different codec, bitrate or language, reachable from one or more
+// sources.
+//
+// Bitrate is expressed in bits per second and Height, for video, in
+// pixels.  The Default variant is the one also written as <enclosure>.
+type PodcastAlternateEnclosure struct {
+	XMLName   xml.Name `xml:"podcast:alternateEnclosure"`
+	Type      string   `xml:"type,attr"`
+	Length    int64    `xml:"length,attr,omitempty"`
+	Bitrate   float64  `xml:"bitrate,attr,omitempty"`
+	Height    int      `xml:"height,attr,omitempty"`
+	Lang      string   `xml:"lang,attr,omitempty"`
+	Title     string   `xml:"title,attr,omitempty"`
+	Rel       string   `xml:"rel,attr,omitempty"`
+	Codecs    string   `xml:"codecs,attr,omitempty"`
+	Default   bool     `xml:"default,attr,omitempty"`
+	Integrity *PodcastIntegrity
+	Sources   []*PodcastSource
+}
+
+// PodcastSource is a URI the alternate enclosure can be fetched from,
+// such as an HTTP, IPFS or torrent link.
+type PodcastSource struct {
+	XMLName     xml.Name `xml:"podcast:source"`
+	URI         string   `xml:"uri,attr"`
+	ContentType string   `xml:"contentType,attr,omitempty"`
+}
+
+// PodcastIntegrity lets apps verify the media of an alternate enclosure,
+// either as a Subresource Integrity hash ("sri") or a PGP signature
+// ("pgp-signature").
+type PodcastIntegrity struct {
+	XMLName xml.Name `xml:"podcast:integrity"`
+	Type    string   `xml:"type,attr"`
+	Value   string   `xml:"value,attr"`
+}
+
+// AddSource adds a source of the alternate enclosure.
+// Calling this method multiple times will APPEND the source.
+func (e *PodcastAlternateEnclosure) AddSource(uri, contentType string) {
+	if len(uri) == 0 {
+		return
+	}
+
+	e.Sources = append(e.Sources, &PodcastSource{
+		URI:         uri,
+		ContentType: contentType,
+	})
+}
+
+// AddIntegrity sets the integrity hash or signature of the alternate
+// enclosure.  Use NewIntegrity to hash the media.
+func (e *PodcastAlternateEnclosure) AddIntegrity(integrityType, value string) {
+	if len(integrityType) == 0 || len(value) == 0 {
+		return
+	}
+
+	e.Integrity = &PodcastIntegrity{
+		Type:  integrityType,
+		Value: value,
+	}
+}
+
+// NewIntegrity hashes the media read from r into a SHA-384 Subresource
+// Integrity value.
+func NewIntegrity(r io.Reader) (*PodcastIntegrity, error) {
+	h := sha512.New384()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, errors.Wrap(err, "podcast.NewIntegrity: io.Copy returned error")
+	}
+	return &PodcastIntegrity{
+		Type:  "sri",
+		Value: "sha384-" + base64.StdEncoding.EncodeToString(h.Sum(nil)),
+	}, nil
+}
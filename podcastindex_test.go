@@ -0,0 +1,101 @@
+package podcast_test
+
+import (
+	"strings"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePodcastGUID(t *testing.T) {
+	t.Parallel()
+
+	guid := podcast.GeneratePodcastGUID("https://mp3s.nashownotes.com/pc20rss.xml")
+
+	assert.Equal(t, "917393e3-1b1e-5cef-ace4-edaa54e1f810", guid)
+}
+
+func TestGeneratePodcastGUIDIgnoresSchemeAndTrailingSlash(t *testing.T) {
+	t.Parallel()
+
+	expected := podcast.GeneratePodcastGUID("podnews.net/rss")
+
+	assert.Equal(t, expected, podcast.GeneratePodcastGUID("https://podnews.net/rss/"))
+	assert.Equal(t, expected, podcast.GeneratePodcastGUID("http://podnews.net/rss"))
+}
+
+func TestEncodePodcastNamespace(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddPodcastGUID("917393e3-1b1e-5cef-ace4-edaa54e1f810")
+	p.AddLocked(true, "owner@example.com")
+	p.AddFunding("https://example.com/donate", "Support the show")
+	p.AddPerson(podcast.PodcastPerson{Name: "Jane Doe", Role: "host"})
+	p.AddLocation("Austin, TX", "geo:30.2672,97.7431", "")
+
+	i := podcast.Item{Title: "title", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 1)
+	i.AddTranscript("http://example.com/1.vtt", "text/vtt", "en", "captions")
+	i.AddChapters("http://example.com/1.json", "application/json+chapters")
+	i.AddSoundbite(73, 60.5, "Best bit")
+	i.AddPodcastSeason(2, "Road Trip")
+	i.AddPodcastEpisode(204.5, "")
+	if _, err := p.AddItem(i); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	out := p.String()
+
+	// assert
+	assert.Contains(t, out, `<podcast:guid>917393e3-1b1e-5cef-ace4-edaa54e1f810</podcast:guid>`)
+	assert.Contains(t, out, `<podcast:locked owner="owner@example.com">yes</podcast:locked>`)
+	assert.Contains(t, out, `<podcast:funding url="https://example.com/donate">Support the show</podcast:funding>`)
+	assert.Contains(t, out, `<podcast:person role="host">Jane Doe</podcast:person>`)
+	assert.Contains(t, out, `<podcast:location geo="geo:30.2672,97.7431">Austin, TX</podcast:location>`)
+	assert.Contains(t, out, `<podcast:transcript url="http://example.com/1.vtt" type="text/vtt" language="en" rel="captions"></podcast:transcript>`)
+	assert.Contains(t, out, `<podcast:chapters url="http://example.com/1.json" type="application/json+chapters"></podcast:chapters>`)
+	assert.Contains(t, out, `<podcast:soundbite startTime="73" duration="60.5">Best bit</podcast:soundbite>`)
+	assert.Contains(t, out, `<podcast:season name="Road Trip">2</podcast:season>`)
+	assert.Contains(t, out, `<podcast:episode>204.5</podcast:episode>`)
+}
+
+func TestNewIntegrity(t *testing.T) {
+	t.Parallel()
+
+	integrity, err := podcast.NewIntegrity(strings.NewReader("alert('Hello, world.');"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sri", integrity.Type)
+	assert.Equal(t, "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO", integrity.Value)
+}
+
+func TestEncodeAlternateEnclosure(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ae := podcast.PodcastAlternateEnclosure{Type: "application/x-mpegURL", Height: 720, Lang: "en", Title: "Video"}
+	ae.AddSource("https://example.com/1.m3u8", "")
+	ae.AddSource("ipfs://QmExample", "application/x-mpegURL")
+	ae.AddIntegrity("sri", "sha384-abc")
+	i := podcast.Item{Title: "title", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 1)
+	i.AddAlternateEnclosure(ae)
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, nil, nil)
+	if _, err := p.AddItem(i); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	out := p.String()
+
+	// assert
+	assert.Contains(t, out, `<podcast:alternateEnclosure type="application/x-mpegURL" height="720" lang="en" title="Video">
+        <podcast:integrity type="sri" value="sha384-abc"></podcast:integrity>
+        <podcast:source uri="https://example.com/1.m3u8"></podcast:source>
+        <podcast:source uri="ipfs://QmExample" contentType="application/x-mpegURL"></podcast:source>
+      </podcast:alternateEnclosure>`)
+}
@@ -0,0 +1,159 @@
+package podcast
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// MediaInfo describes a local media file, as found by ProbeMedia.
+//
+// Title and Artwork come from the tags embedded in the file, if any.
+// ArtworkType is the MIME type of the Artwork, such as "image/jpeg".
+type MediaInfo struct {
+	Size        int64
+	Duration    time.Duration
+	Type        EnclosureType
+	Title       string
+	Artwork     []byte
+	ArtworkType string
+}
+
+// ProbeMediaFile probes the local media file, see ProbeMedia.
+func ProbeMediaFile(filename string) (*MediaInfo, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.ProbeMediaFile: os.Open returned error")
+	}
+	defer f.Close()
+
+	info, err := ProbeMedia(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.ProbeMediaFile: "+filename)
+	}
+	return info, nil
+}
+
+// ProbeMedia reads the size, duration, type and embedded title and
+// artwork of MP3, M4A/MP4, Ogg (Opus and Vorbis) and WAV media.
+//
+// Only the headers and tags are read: the rest of the media is skipped
+// over with Seek.  The duration of an MP3 without a Xing or VBRI header
+// is estimated from the bitrate of its first frame.
+func ProbeMedia(r io.ReadSeeker) (*MediaInfo, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.ProbeMedia: r.Seek returned error")
+	}
+	head, err := readAt(r, 0, 12)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.ProbeMedia: reading header")
+	}
+
+	info := &MediaInfo{Size: size}
+	switch {
+	case bytes.HasPrefix(head, []byte("RIFF")) && string(head[8:12]) == "WAVE":
+		err = probeWAV(r, info)
+	case bytes.HasPrefix(head, []byte("OggS")):
+		err = probeOgg(r, info)
+	case string(head[4:8]) == "ftyp":
+		err = probeMP4(r, info)
+	case bytes.HasPrefix(head, []byte("ID3")):
+		err = probeMP3(r, info)
+	default:
+		if _, ok := parseMPEGHeader(head); !ok {
+			return nil, errors.New("podcast.ProbeMedia: unknown media format")
+		}
+		err = probeMP3(r, info)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.ProbeMedia")
+	}
+	return info, nil
+}
+
+// readAt reads exactly n bytes at offset.
+func readAt(r io.ReadSeeker, offset int64, n int) ([]byte, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readUpTo reads at most n bytes at offset, stopping early at the end of
+// the media.
+func readUpTo(r io.ReadSeeker, offset int64, n int) ([]byte, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	read, err := io.ReadFull(r, b)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return b[:read], nil
+}
+
+// secondsDuration converts a count of samples at a rate into a Duration.
+func secondsDuration(samples, rate float64) time.Duration {
+	if rate <= 0 || samples <= 0 {
+		return 0
+	}
+	return time.Duration(samples / rate * float64(time.Second))
+}
+
+// decodeLatin1 decodes ISO-8859-1 text.
+func decodeLatin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for n, c := range b {
+		runes[n] = rune(c)
+	}
+	return string(runes)
+}
+
+// decodeUTF16 decodes UTF-16 text, big endian unless a byte order mark
+// says otherwise.
+func decodeUTF16(b []byte) string {
+	littleEndian := false
+	if len(b) >= 2 {
+		switch {
+		case b[0] == 0xFF && b[1] == 0xFE:
+			littleEndian, b = true, b[2:]
+		case b[0] == 0xFE && b[1] == 0xFF:
+			b = b[2:]
+		}
+	}
+	units := make([]uint16, len(b)/2)
+	for n := range units {
+		if littleEndian {
+			units[n] = uint16(b[2*n]) | uint16(b[2*n+1])<<8
+		} else {
+			units[n] = uint16(b[2*n])<<8 | uint16(b[2*n+1])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// imageMIME turns the short image formats of some tags, such as "JPG" or
+// "png", into a MIME type.
+func imageMIME(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch {
+	case strings.Contains(format, "/"):
+		return format
+	case format == "jpg" || format == "jpeg":
+		return "image/jpeg"
+	case len(format) != 0:
+		return "image/" + format
+	}
+	return ""
+}
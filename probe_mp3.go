@@ -0,0 +1,304 @@
+package podcast
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: https://id3.org/id3v2.4.0-structure
+// and http://www.mp3-tech.org/programmer/frame_header.html
+//
+
+// mp3SyncWindow is how far past the ID3 tag the first frame is looked for.
+const mp3SyncWindow = 64 * 1024
+
+var (
+	mpegSampleRates = [4][3]int{
+		{11025, 12000, 8000},  // MPEG 2.5
+		{},                    // reserved
+		{22050, 24000, 16000}, // MPEG 2
+		{44100, 48000, 32000}, // MPEG 1
+	}
+	mpeg1Bitrates = [4][15]int{
+		{},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},     // Layer III
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},    // Layer II
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448}, // Layer I
+	}
+	mpeg2Bitrates = [4][15]int{
+		{},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},      // Layer III
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},      // Layer II
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256}, // Layer I
+	}
+)
+
+// mpegHeader is the decoded 4 byte header of an MPEG audio frame.
+type mpegHeader struct {
+	mpeg1      bool
+	layer      int
+	bitrate    int // bits per second
+	sampleRate int
+	padding    int
+	mono       bool
+}
+
+// parseMPEGHeader decodes the MPEG audio frame header at the start of b.
+func parseMPEGHeader(b []byte) (mpegHeader, bool) {
+	var h mpegHeader
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return h, false
+	}
+	version := b[1] >> 3 & 3
+	layerBits := b[1] >> 1 & 3
+	bitrateIndex := b[2] >> 4
+	rateIndex := b[2] >> 2 & 3
+	if version == 1 || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return h, false
+	}
+
+	h.mpeg1 = version == 3
+	h.layer = 4 - int(layerBits)
+	if h.mpeg1 {
+		h.bitrate = mpeg1Bitrates[layerBits][bitrateIndex] * 1000
+	} else {
+		h.bitrate = mpeg2Bitrates[layerBits][bitrateIndex] * 1000
+	}
+	h.sampleRate = mpegSampleRates[version][rateIndex]
+	h.padding = int(b[2] >> 1 & 1)
+	h.mono = b[3]>>6 == 3
+	return h, true
+}
+
+// samples returns the number of samples per frame.
+func (h mpegHeader) samples() int {
+	switch {
+	case h.layer == 1:
+		return 384
+	case h.layer == 3 && !h.mpeg1:
+		return 576
+	}
+	return 1152
+}
+
+// frameLength returns the length in bytes of the frame, header included.
+func (h mpegHeader) frameLength() int {
+	if h.layer == 1 {
+		return (12*h.bitrate/h.sampleRate + h.padding) * 4
+	}
+	return h.samples()/8*h.bitrate/h.sampleRate + h.padding
+}
+
+// xingOffset returns where the Xing header follows the side information.
+func (h mpegHeader) xingOffset() int {
+	switch {
+	case h.mpeg1 && !h.mono:
+		return 4 + 32
+	case h.mpeg1 || !h.mono:
+		return 4 + 17
+	}
+	return 4 + 9
+}
+
+func probeMP3(r io.ReadSeeker, info *MediaInfo) error {
+	info.Type = MP3
+
+	var start int64
+	hdr, err := readAt(r, 0, 10)
+	if err == nil && bytes.HasPrefix(hdr, []byte("ID3")) {
+		size := int64(syncsafe(hdr[6:10]))
+		// the size is checked before the tag is read into memory, as a
+		// corrupt header claims up to 256 MiB
+		if 10+size > info.Size {
+			return errors.New("ID3v2 tag is larger than the media")
+		}
+		tag, err := readAt(r, 10, int(size))
+		if err != nil {
+			return errors.Wrap(err, "reading ID3v2 tag")
+		}
+		parseID3v2(hdr, tag, info)
+		start = 10 + size
+		if hdr[5]&0x10 != 0 {
+			start += 10
+		}
+	}
+
+	buf, err := readUpTo(r, start, mp3SyncWindow)
+	if err != nil {
+		return errors.Wrap(err, "reading MPEG frames")
+	}
+	for off := 0; off+4 <= len(buf); off++ {
+		h, ok := parseMPEGHeader(buf[off:])
+		if !ok {
+			continue
+		}
+		// a second header right after the frame rules out a false sync
+		if next := off + h.frameLength(); next+4 <= len(buf) {
+			if _, ok := parseMPEGHeader(buf[next:]); !ok {
+				continue
+			}
+		}
+
+		frame := buf[off:]
+		if frames, ok := vbrFrames(h, frame); ok {
+			info.Duration = secondsDuration(float64(frames)*float64(h.samples()), float64(h.sampleRate))
+			return nil
+		}
+		audio := info.Size - start - int64(off)
+		if tail, err := readAt(r, info.Size-128, 3); err == nil && string(tail) == "TAG" {
+			audio -= 128
+		}
+		info.Duration = secondsDuration(float64(audio*8), float64(h.bitrate))
+		return nil
+	}
+	return errors.New("no MPEG audio frame found")
+}
+
+// vbrFrames returns the frame count of the Xing, Info or VBRI header in
+// the first frame of a variable bitrate MP3.
+func vbrFrames(h mpegHeader, frame []byte) (uint32, bool) {
+	if x := h.xingOffset(); len(frame) >= x+12 {
+		tag := string(frame[x : x+4])
+		flags := binary.BigEndian.Uint32(frame[x+4:])
+		if (tag == "Xing" || tag == "Info") && flags&1 != 0 {
+			return binary.BigEndian.Uint32(frame[x+8:]), true
+		}
+	}
+	if v := 4 + 32; len(frame) >= v+18 && string(frame[v:v+4]) == "VBRI" {
+		return binary.BigEndian.Uint32(frame[v+14:]), true
+	}
+	return 0, false
+}
+
+// parseID3v2 reads the title (TIT2) and artwork (APIC) of an ID3v2.2,
+// v2.3 or v2.4 tag.  The front cover is preferred over other pictures.
+func parseID3v2(hdr, tag []byte, info *MediaInfo) {
+	major := hdr[3]
+	flags := hdr[5]
+	if flags&0x80 != 0 && major < 4 {
+		tag = bytes.Replace(tag, []byte{0xFF, 0x00}, []byte{0xFF}, -1)
+	}
+
+	pos := 0
+	if flags&0x40 != 0 && len(tag) >= 4 {
+		if major >= 4 {
+			pos = syncsafe(tag[:4])
+		} else {
+			pos = int(binary.BigEndian.Uint32(tag)) + 4
+		}
+	}
+
+	idLen, headerLen := 4, 10
+	if major == 2 {
+		idLen, headerLen = 3, 6
+	}
+	frontCover := false
+	for pos >= 0 && pos+headerLen <= len(tag) && tag[pos] != 0 {
+		id := string(tag[pos : pos+idLen])
+		var size int
+		switch major {
+		case 2:
+			size = int(tag[pos+3])<<16 | int(tag[pos+4])<<8 | int(tag[pos+5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(tag[pos+4:]))
+		default:
+			size = syncsafe(tag[pos+4 : pos+8])
+		}
+		body := pos + headerLen
+		if size < 0 || body+size > len(tag) {
+			return
+		}
+		frame := tag[body : body+size]
+		pos = body + size
+
+		switch id {
+		case "TIT2", "TT2":
+			if len(info.Title) == 0 {
+				info.Title = decodeID3Text(frame)
+			}
+		case "APIC", "PIC":
+			mimeType, pictureType, data, ok := parseID3Picture(frame, id == "PIC")
+			if !ok || frontCover || (info.Artwork != nil && pictureType != 3) {
+				continue
+			}
+			info.Artwork, info.ArtworkType = data, mimeType
+			frontCover = pictureType == 3
+		}
+	}
+}
+
+// parseID3Picture splits an APIC (or v2.2 PIC) frame into the MIME type,
+// the picture type and the image data.
+func parseID3Picture(frame []byte, v22 bool) (string, byte, []byte, bool) {
+	if len(frame) < 2 {
+		return "", 0, nil, false
+	}
+	enc := frame[0]
+	rest := frame[1:]
+
+	var mimeType string
+	if v22 {
+		if len(rest) < 3 {
+			return "", 0, nil, false
+		}
+		mimeType, rest = imageMIME(string(rest[:3])), rest[3:]
+	} else {
+		end := bytes.IndexByte(rest, 0)
+		if end < 0 {
+			return "", 0, nil, false
+		}
+		mimeType, rest = imageMIME(string(rest[:end])), rest[end+1:]
+	}
+	if len(rest) < 1 || mimeType == "image/-->" {
+		return "", 0, nil, false
+	}
+	pictureType := rest[0]
+	rest = rest[1:]
+
+	end, width := id3Terminator(rest, enc)
+	if end < 0 {
+		return "", 0, nil, false
+	}
+	return mimeType, pictureType, rest[end+width:], true
+}
+
+// decodeID3Text decodes the first string of a text frame.
+func decodeID3Text(frame []byte) string {
+	if len(frame) < 1 {
+		return ""
+	}
+	enc := frame[0]
+	text := frame[1:]
+	if end, _ := id3Terminator(text, enc); end >= 0 {
+		text = text[:end]
+	}
+	switch enc {
+	case 0:
+		return decodeLatin1(text)
+	case 1, 2:
+		return decodeUTF16(text)
+	}
+	return string(text)
+}
+
+// id3Terminator returns the index and width of the string terminator of
+// the text encoding, which is two bytes wide for UTF-16.
+func id3Terminator(b []byte, enc byte) (int, int) {
+	if enc == 1 || enc == 2 {
+		for n := 0; n+1 < len(b); n += 2 {
+			if b[n] == 0 && b[n+1] == 0 {
+				return n, 2
+			}
+		}
+		return -1, 2
+	}
+	return bytes.IndexByte(b, 0), 1
+}
+
+// syncsafe decodes a 28 bit synchsafe integer.
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
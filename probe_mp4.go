@@ -0,0 +1,169 @@
+package podcast
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: ISO/IEC 14496-12 and
+// https://developer.apple.com/documentation/quicktime-file-format
+//
+
+// mp4Probe walks the boxes (atoms) of an MP4 or QuickTime file.
+type mp4Probe struct {
+	r         io.ReadSeeker
+	info      *MediaInfo
+	brand     string
+	video     bool
+	timescale uint64
+	duration  uint64
+}
+
+func probeMP4(r io.ReadSeeker, info *MediaInfo) error {
+	p := &mp4Probe{r: r, info: info}
+	if err := p.walk(0, info.Size, ""); err != nil {
+		return err
+	}
+	if p.timescale == 0 {
+		return errors.New("no mvhd box found")
+	}
+	info.Duration = secondsDuration(float64(p.duration), float64(p.timescale))
+
+	switch {
+	case p.brand == "M4V " || p.brand == "M4VH" || p.brand == "M4VP":
+		info.Type = M4V
+	case p.brand == "qt  ":
+		info.Type = MOV
+	case p.video:
+		info.Type = MP4
+	default:
+		info.Type = M4A
+	}
+	return nil
+}
+
+// walk reads the boxes between start and end, descending into those that
+// lead to the movie header, the track handlers and the iTunes metadata.
+func (p *mp4Probe) walk(start, end int64, parent string) error {
+	for off := start; off+8 <= end; {
+		hdr, err := readAt(p.r, off, 8)
+		if err != nil {
+			return errors.Wrap(err, "reading MP4 box")
+		}
+		size := int64(binary.BigEndian.Uint32(hdr))
+		typ := string(hdr[4:8])
+		headerLen := int64(8)
+		switch size {
+		case 0:
+			size = end - off
+		case 1:
+			large, err := readAt(p.r, off+8, 8)
+			if err != nil {
+				return errors.Wrap(err, "reading MP4 box")
+			}
+			size = int64(binary.BigEndian.Uint64(large))
+			headerLen = 16
+		}
+		if size < headerLen || off+size > end {
+			return errors.New("invalid MP4 box " + typ)
+		}
+		body, bodyEnd := off+headerLen, off+size
+		off = bodyEnd
+
+		switch {
+		case typ == "moov" || typ == "trak" || typ == "mdia" || typ == "udta" || typ == "ilst":
+			err = p.walk(body, bodyEnd, typ)
+		case typ == "meta":
+			// iTunes writes meta as a full box, QuickTime does not
+			if peek, _ := readAt(p.r, body+8, 4); string(peek) == "hdlr" || string(peek) == "ilst" {
+				body += 4
+			}
+			err = p.walk(body, bodyEnd, typ)
+		case parent == "ilst" && (typ == "\xa9nam" || typ == "covr"):
+			err = p.walk(body, bodyEnd, typ)
+		case typ == "ftyp":
+			err = p.ftyp(body)
+		case typ == "mvhd":
+			err = p.mvhd(body)
+		case typ == "hdlr" && parent == "mdia":
+			err = p.hdlr(body)
+		case typ == "data" && (parent == "\xa9nam" || parent == "covr"):
+			err = p.data(body, bodyEnd, parent)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *mp4Probe) ftyp(body int64) error {
+	b, err := readAt(p.r, body, 4)
+	if err != nil {
+		return errors.Wrap(err, "reading ftyp box")
+	}
+	p.brand = string(b)
+	return nil
+}
+
+func (p *mp4Probe) mvhd(body int64) error {
+	b, err := readAt(p.r, body, 32)
+	if err != nil {
+		return errors.Wrap(err, "reading mvhd box")
+	}
+	if b[0] == 1 {
+		p.timescale = uint64(binary.BigEndian.Uint32(b[20:]))
+		p.duration = binary.BigEndian.Uint64(b[24:])
+	} else {
+		p.timescale = uint64(binary.BigEndian.Uint32(b[12:]))
+		p.duration = uint64(binary.BigEndian.Uint32(b[16:]))
+	}
+	return nil
+}
+
+func (p *mp4Probe) hdlr(body int64) error {
+	b, err := readAt(p.r, body, 12)
+	if err != nil {
+		return errors.Wrap(err, "reading hdlr box")
+	}
+	if string(b[8:12]) == "vide" {
+		p.video = true
+	}
+	return nil
+}
+
+// data reads the value of an iTunes metadata item: the title, or the
+// cover artwork whose format is given by the well-known type.
+func (p *mp4Probe) data(body, end int64, parent string) error {
+	if end-body < 8 {
+		return nil
+	}
+	b, err := readAt(p.r, body, int(end-body))
+	if err != nil {
+		return errors.Wrap(err, "reading data box")
+	}
+	value := b[8:]
+	switch parent {
+	case "\xa9nam":
+		if len(p.info.Title) == 0 {
+			p.info.Title = strings.TrimRight(string(value), "\x00")
+		}
+	case "covr":
+		if p.info.Artwork != nil {
+			return nil
+		}
+		switch binary.BigEndian.Uint32(b) & 0xFFFFFF {
+		case 13:
+			p.info.ArtworkType = "image/jpeg"
+		case 14:
+			p.info.ArtworkType = "image/png"
+		case 27:
+			p.info.ArtworkType = "image/bmp"
+		}
+		p.info.Artwork = value
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package podcast
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: https://tools.ietf.org/html/rfc3533, https://tools.ietf.org/html/rfc7845
+// and https://xiph.org/vorbis/doc/Vorbis_I_spec.html
+//
+
+const (
+	// oggMaxPage is the largest possible Ogg page, header included.
+	oggMaxPage = 27 + 255 + 255*255
+
+	// oggMaxHeaders bounds how much is read to find the comment header,
+	// which can be large when it embeds artwork.
+	oggMaxHeaders = 16 * 1024 * 1024
+)
+
+// oggPage is the header of an Ogg page.
+type oggPage struct {
+	granule  uint64
+	serial   uint32
+	segments []byte
+}
+
+func probeOgg(r io.ReadSeeker, info *MediaInfo) error {
+	packets, serial, err := oggHeaderPackets(r)
+	if err != nil {
+		return err
+	}
+
+	var rate, preSkip float64
+	var comments []byte
+	switch head := packets[0]; {
+	case bytes.HasPrefix(head, []byte("OpusHead")) && len(head) >= 12:
+		info.Type = OPUS
+		rate = 48000
+		preSkip = float64(binary.LittleEndian.Uint16(head[10:]))
+		if bytes.HasPrefix(packets[1], []byte("OpusTags")) {
+			comments = packets[1][8:]
+		}
+	case bytes.HasPrefix(head, []byte("\x01vorbis")) && len(head) >= 16:
+		info.Type = OGG
+		rate = float64(binary.LittleEndian.Uint32(head[12:]))
+		if bytes.HasPrefix(packets[1], []byte("\x03vorbis")) {
+			comments = packets[1][7:]
+		}
+	default:
+		return errors.New("unsupported Ogg codec")
+	}
+	parseVorbisComments(comments, info)
+
+	granule, err := oggLastGranule(r, info.Size, serial)
+	if err != nil {
+		return err
+	}
+	info.Duration = secondsDuration(float64(granule)-preSkip, rate)
+	return nil
+}
+
+// readOggPage reads the page header at the current position of r.
+func readOggPage(r io.Reader) (oggPage, error) {
+	var page oggPage
+	hdr := make([]byte, 27)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return page, err
+	}
+	if string(hdr[:4]) != "OggS" {
+		return page, errors.New("invalid Ogg page")
+	}
+	page.granule = binary.LittleEndian.Uint64(hdr[6:])
+	page.serial = binary.LittleEndian.Uint32(hdr[14:])
+	page.segments = make([]byte, hdr[26])
+	if _, err := io.ReadFull(r, page.segments); err != nil {
+		return page, err
+	}
+	return page, nil
+}
+
+// oggHeaderPackets returns the identification and comment packets of the
+// first logical stream, and its serial number.
+func oggHeaderPackets(r io.ReadSeeker) ([][]byte, uint32, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, errors.Wrap(err, "reading Ogg headers")
+	}
+
+	var packets [][]byte
+	var packet []byte
+	var serial uint32
+	read := 0
+	for first := true; len(packets) < 2; first = false {
+		page, err := readOggPage(r)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "reading Ogg headers")
+		}
+		if first {
+			serial = page.serial
+		}
+		size := 0
+		for _, s := range page.segments {
+			size += int(s)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, 0, errors.Wrap(err, "reading Ogg headers")
+		}
+		if read += size; read > oggMaxHeaders {
+			return nil, 0, errors.New("Ogg headers are too large")
+		}
+		if page.serial != serial {
+			continue
+		}
+
+		// a segment shorter than 255 bytes ends the packet
+		for _, s := range page.segments {
+			packet, data = append(packet, data[:s]...), data[s:]
+			if s < 255 {
+				packets = append(packets, packet)
+				packet = nil
+			}
+		}
+	}
+	return packets, serial, nil
+}
+
+// oggLastGranule returns the granule position of the last page of the
+// stream, which is the number of samples it holds.
+func oggLastGranule(r io.ReadSeeker, size int64, serial uint32) (uint64, error) {
+	start := size - oggMaxPage
+	if start < 0 {
+		start = 0
+	}
+	tail, err := readUpTo(r, start, int(size-start))
+	if err != nil {
+		return 0, errors.Wrap(err, "reading last Ogg page")
+	}
+	for n := bytes.LastIndex(tail, []byte("OggS")); n >= 0; n = bytes.LastIndex(tail[:n], []byte("OggS")) {
+		page, err := readOggPage(bytes.NewReader(tail[n:]))
+		if err == nil && page.serial == serial && page.granule != ^uint64(0) {
+			return page.granule, nil
+		}
+	}
+	return 0, errors.New("no Ogg page with a granule position found")
+}
+
+// parseVorbisComments reads the TITLE and METADATA_BLOCK_PICTURE of a
+// Vorbis comment header, as used by both Vorbis and Opus.
+func parseVorbisComments(b []byte, info *MediaInfo) {
+	next := func() ([]byte, bool) {
+		if len(b) < 4 {
+			return nil, false
+		}
+		n := binary.LittleEndian.Uint32(b)
+		if uint64(n) > uint64(len(b)-4) {
+			return nil, false
+		}
+		field := b[4 : 4+n]
+		b = b[4+n:]
+		return field, true
+	}
+
+	if _, ok := next(); !ok { // vendor
+		return
+	}
+	if len(b) < 4 {
+		return
+	}
+	count := binary.LittleEndian.Uint32(b)
+	b = b[4:]
+	frontCover := false
+	for ; count > 0; count-- {
+		comment, ok := next()
+		if !ok {
+			return
+		}
+		eq := bytes.IndexByte(comment, '=')
+		if eq < 0 {
+			continue
+		}
+		value := string(comment[eq+1:])
+		switch strings.ToUpper(string(comment[:eq])) {
+		case "TITLE":
+			if len(info.Title) == 0 {
+				info.Title = value
+			}
+		case "METADATA_BLOCK_PICTURE":
+			picture, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			mimeType, pictureType, data, ok := parseFLACPicture(picture)
+			if !ok || frontCover || (info.Artwork != nil && pictureType != 3) {
+				continue
+			}
+			info.Artwork, info.ArtworkType = data, mimeType
+			frontCover = pictureType == 3
+		}
+	}
+}
+
+// parseFLACPicture splits a FLAC picture block into the MIME type, the
+// picture type and the image data.
+func parseFLACPicture(b []byte) (string, uint32, []byte, bool) {
+	field := func() ([]byte, bool) {
+		if len(b) < 4 {
+			return nil, false
+		}
+		n := binary.BigEndian.Uint32(b)
+		if uint64(n) > uint64(len(b)-4) {
+			return nil, false
+		}
+		f := b[4 : 4+n]
+		b = b[4+n:]
+		return f, true
+	}
+
+	if len(b) < 4 {
+		return "", 0, nil, false
+	}
+	pictureType := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	mimeType, ok := field()
+	if !ok {
+		return "", 0, nil, false
+	}
+	if _, ok := field(); !ok { // description
+		return "", 0, nil, false
+	}
+	if len(b) < 16 { // width, height, depth and colors
+		return "", 0, nil, false
+	}
+	b = b[16:]
+	data, ok := field()
+	if !ok {
+		return "", 0, nil, false
+	}
+	return imageMIME(string(mimeType)), pictureType, data, true
+}
@@ -0,0 +1,373 @@
+package podcast_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func be32(n int) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(n))
+	return b
+}
+
+func le16(n int) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, uint16(n))
+	return b
+}
+
+func le32(n int) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(n))
+	return b
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func syncsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+// id3v2 builds an ID3v2 tag of the major version from its frames.
+func id3v2(major byte, frames ...[]byte) []byte {
+	body := join(append(frames, make([]byte, 10))...)
+	return join([]byte{'I', 'D', '3', major, 0, 0}, syncsafe(len(body)), body)
+}
+
+func id3Frame(major byte, id string, body []byte) []byte {
+	size := be32(len(body))
+	if major == 4 {
+		size = syncsafe(len(body))
+	}
+	return join([]byte(id), size, []byte{0, 0}, body)
+}
+
+func utf16Text(s string) []byte {
+	b := []byte{1, 0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, le16(int(u))...)
+	}
+	return append(b, 0, 0)
+}
+
+// mpegFrames builds count MPEG 1 Layer III frames at 128 kbps and
+// 44.1 kHz, with tag written in the first one.
+func mpegFrames(count int, tag []byte) []byte {
+	var b []byte
+	for n := 0; n < count; n++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+		if n == 0 {
+			copy(frame[36:], tag)
+		}
+		b = append(b, frame...)
+	}
+	return b
+}
+
+func mp4Box(typ string, children ...[]byte) []byte {
+	body := join(children...)
+	return join(be32(8+len(body)), []byte(typ), body)
+}
+
+func mp4File(brand, handler string) []byte {
+	mvhd := make([]byte, 100)
+	copy(mvhd[12:], be32(1000))
+	copy(mvhd[16:], be32(90500))
+	hdlr := func(handlerType string) []byte {
+		return mp4Box("hdlr", make([]byte, 8), []byte(handlerType), make([]byte, 13))
+	}
+	return join(
+		mp4Box("ftyp", []byte(brand), be32(0), []byte("isom")),
+		mp4Box("moov",
+			mp4Box("mvhd", mvhd),
+			mp4Box("trak", mp4Box("mdia", hdlr(handler))),
+			mp4Box("udta", mp4Box("meta", make([]byte, 4), hdlr("mdir"), mp4Box("ilst",
+				mp4Box("\xa9nam", mp4Box("data", be32(1), be32(0), []byte("MP4 Episode"))),
+				mp4Box("covr", mp4Box("data", be32(13), be32(0), []byte("JPEGDATA"))),
+			))),
+		),
+		mp4Box("mdat", make([]byte, 64)),
+	)
+}
+
+func oggPage(granule uint64, serial int, packets ...[]byte) []byte {
+	var segments, data []byte
+	for _, p := range packets {
+		data = append(data, p...)
+		n := len(p)
+		for ; n >= 255; n -= 255 {
+			segments = append(segments, 255)
+		}
+		segments = append(segments, byte(n))
+	}
+	g := make([]byte, 8)
+	binary.LittleEndian.PutUint64(g, granule)
+	return join([]byte("OggS"), []byte{0, 0}, g, le32(serial), le32(0), le32(0), []byte{byte(len(segments))}, segments, data)
+}
+
+func vorbisComments(comments ...string) []byte {
+	b := join(le32(4), []byte("test"), le32(len(comments)))
+	for _, c := range comments {
+		b = join(b, le32(len(c)), []byte(c))
+	}
+	return b
+}
+
+func flacPicture(pictureType int, mimeType string, data []byte) string {
+	b := join(be32(pictureType), be32(len(mimeType)), []byte(mimeType), be32(0), make([]byte, 16), be32(len(data)), data)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func probe(t *testing.T, media []byte) *podcast.MediaInfo {
+	info, err := podcast.ProbeMedia(bytes.NewReader(media))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestProbeMediaMP3CBR(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tag := id3v2(3,
+		id3Frame(3, "TIT2", utf16Text("Épisode 1")),
+		id3Frame(3, "APIC", join([]byte{0}, []byte("image/png\x00"), []byte{4}, []byte("back\x00"), []byte("BACK"))),
+		id3Frame(3, "APIC", join([]byte{0}, []byte("image/jpeg\x00"), []byte{3}, []byte("cover\x00"), []byte("FRONT"))),
+	)
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+	media := join(tag, mpegFrames(100, nil), id3v1)
+
+	// act
+	info := probe(t, media)
+
+	// assert
+	assert.Equal(t, int64(len(media)), info.Size)
+	assert.Equal(t, podcast.MP3, info.Type)
+	assert.Equal(t, 2606250*time.Microsecond, info.Duration)
+	assert.Equal(t, "Épisode 1", info.Title)
+	assert.Equal(t, []byte("FRONT"), info.Artwork)
+	assert.Equal(t, "image/jpeg", info.ArtworkType)
+}
+
+func TestProbeMediaMP3Xing(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tag := id3v2(4, id3Frame(4, "TIT2", []byte("\x03Xing Episode\x00")))
+	media := join(tag, mpegFrames(3, join([]byte("Xing"), be32(1), be32(1000))))
+
+	// act
+	info := probe(t, media)
+
+	// assert
+	assert.Equal(t, "Xing Episode", info.Title)
+	assert.Equal(t, time.Duration(1000*1152)*time.Second/44100, info.Duration)
+	assert.Nil(t, info.Artwork)
+}
+
+func TestProbeMediaMP3VBRI(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	media := mpegFrames(3, join([]byte("VBRI"), make([]byte, 10), be32(2000)))
+
+	// act
+	info := probe(t, media)
+
+	// assert
+	assert.Equal(t, podcast.MP3, info.Type)
+	assert.Equal(t, time.Duration(2000*1152)*time.Second/44100, info.Duration)
+	assert.Len(t, info.Title, 0)
+}
+
+func TestProbeMediaM4A(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	media := mp4File("M4A ", "soun")
+
+	// act
+	info := probe(t, media)
+
+	// assert
+	assert.Equal(t, podcast.M4A, info.Type)
+	assert.Equal(t, 90500*time.Millisecond, info.Duration)
+	assert.Equal(t, "MP4 Episode", info.Title)
+	assert.Equal(t, []byte("JPEGDATA"), info.Artwork)
+	assert.Equal(t, "image/jpeg", info.ArtworkType)
+}
+
+func TestProbeMediaMP4Video(t *testing.T) {
+	t.Parallel()
+
+	info := probe(t, mp4File("isom", "vide"))
+
+	assert.Equal(t, podcast.MP4, info.Type)
+	assert.Equal(t, 90500*time.Millisecond, info.Duration)
+}
+
+func TestProbeMediaOpus(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	head := join([]byte("OpusHead"), []byte{1, 2}, le16(312), le32(48000), le16(0), []byte{0})
+	tags := join([]byte("OpusTags"), vorbisComments(
+		"title=Opus Episode",
+		"METADATA_BLOCK_PICTURE="+flacPicture(3, "image/png", []byte("PNGDATA")),
+	))
+	media := join(
+		oggPage(0, 7, head),
+		oggPage(0, 7, tags),
+		oggPage(48000*5+312, 7, make([]byte, 100)),
+	)
+
+	// act
+	info := probe(t, media)
+
+	// assert
+	assert.Equal(t, podcast.OPUS, info.Type)
+	assert.Equal(t, 5*time.Second, info.Duration)
+	assert.Equal(t, "Opus Episode", info.Title)
+	assert.Equal(t, []byte("PNGDATA"), info.Artwork)
+	assert.Equal(t, "image/png", info.ArtworkType)
+}
+
+func TestProbeMediaVorbis(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	head := join([]byte("\x01vorbis"), le32(0), []byte{2}, le32(44100), make([]byte, 14))
+	comments := join([]byte("\x03vorbis"), vorbisComments("TITLE=Vorbis Episode"))
+	long := bytes.Repeat([]byte{1}, 600)
+	media := join(
+		oggPage(0, 9, head),
+		oggPage(0, 9, comments, long),
+		oggPage(44100*3, 9, make([]byte, 100)),
+		oggPage(1000, 10, make([]byte, 10)),
+	)
+
+	// act
+	info := probe(t, media)
+
+	// assert
+	assert.Equal(t, podcast.OGG, info.Type)
+	assert.Equal(t, 3*time.Second, info.Duration)
+	assert.Equal(t, "Vorbis Episode", info.Title)
+}
+
+func TestProbeMediaWAV(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	fmtChunk := join(le16(1), le16(2), le32(44100), le32(176400), le16(4), le16(16))
+	list := join([]byte("INFO"), []byte("INAM"), le32(11), []byte("Wave Title\x00"), []byte{0})
+	body := join(
+		[]byte("WAVE"),
+		[]byte("fmt "), le32(len(fmtChunk)), fmtChunk,
+		[]byte("LIST"), le32(len(list)), list,
+		[]byte("data"), le32(352800), make([]byte, 352800),
+	)
+	media := join([]byte("RIFF"), le32(len(body)), body)
+
+	// act
+	info := probe(t, media)
+
+	// assert
+	assert.Equal(t, podcast.WAV, info.Type)
+	assert.Equal(t, 2*time.Second, info.Duration)
+	assert.Equal(t, "Wave Title", info.Title)
+}
+
+func TestProbeMediaUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := podcast.ProbeMedia(bytes.NewReader([]byte("%PDF-1.4 not media")))
+
+	assert.Error(t, err)
+}
+
+func TestProbeMediaTruncated(t *testing.T) {
+	t.Parallel()
+
+	media := mp4File("M4A ", "soun")
+
+	_, err := podcast.ProbeMedia(bytes.NewReader(media[:60]))
+
+	assert.Error(t, err)
+}
+
+func TestProbeMediaID3TooLarge(t *testing.T) {
+	t.Parallel()
+
+	media := join([]byte{'I', 'D', '3', 4, 0, 0}, syncsafe(0x0FFFFFFF), make([]byte, 64))
+
+	_, err := podcast.ProbeMedia(bytes.NewReader(media))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ID3v2 tag is larger than the media")
+}
+
+func TestItemAddMediaFile(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	f, err := ioutil.TempFile("", "probe-*.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	media := join(id3v2(3, id3Frame(3, "TIT2", []byte("\x00Tagged"))), mpegFrames(400, nil))
+	if _, err := f.Write(media); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	i := podcast.Item{}
+
+	// act
+	err = i.AddMediaFile("http://example.com/1.mp3", f.Name())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Tagged", i.Title)
+	assert.Equal(t, "http://example.com/1.mp3", i.Enclosure.URL)
+	assert.Equal(t, podcast.MP3, i.Enclosure.Type)
+	assert.Equal(t, int64(len(media)), i.Enclosure.Length)
+	assert.Equal(t, "10", i.IDuration)
+}
+
+func TestItemAddMediaKeepsTitle(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{Title: "Mine"}
+
+	i.AddMedia("http://example.com/1.wav", &podcast.MediaInfo{Size: 10, Duration: 90 * time.Second, Type: podcast.WAV, Title: "Theirs"})
+
+	assert.Equal(t, "Mine", i.Title)
+	assert.Equal(t, "audio/wav", i.Enclosure.TypeFormatted)
+	assert.Equal(t, "90", i.IDuration)
+}
+
+func TestItemAddMediaFileMissing(t *testing.T) {
+	t.Parallel()
+
+	i := podcast.Item{}
+
+	err := i.AddMediaFile("http://example.com/1.mp3", "testdata/does-not-exist.mp3")
+
+	assert.Error(t, err)
+	assert.Nil(t, i.Enclosure)
+}
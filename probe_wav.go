@@ -0,0 +1,82 @@
+package podcast
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: http://soundfile.sapp.org/doc/WaveFormat/
+//
+
+// wavMaxList bounds the LIST chunk read for the title.
+const wavMaxList = 1024 * 1024
+
+func probeWAV(r io.ReadSeeker, info *MediaInfo) error {
+	info.Type = WAV
+
+	var byteRate uint32
+	dataSize := int64(-1)
+	for off := int64(12); off+8 <= info.Size; {
+		hdr, err := readAt(r, off, 8)
+		if err != nil {
+			return errors.Wrap(err, "reading WAV chunk")
+		}
+		id := string(hdr[:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:]))
+		body := off + 8
+		if body+size > info.Size {
+			// streamed files leave the size of the data chunk unset
+			size = info.Size - body
+		}
+
+		switch id {
+		case "fmt ":
+			b, err := readAt(r, body, 16)
+			if err != nil {
+				return errors.Wrap(err, "reading WAV fmt chunk")
+			}
+			byteRate = binary.LittleEndian.Uint32(b[8:])
+		case "data":
+			dataSize = size
+		case "LIST":
+			if size <= wavMaxList {
+				b, err := readAt(r, body, int(size))
+				if err != nil {
+					return errors.Wrap(err, "reading WAV LIST chunk")
+				}
+				parseWAVInfo(b, info)
+			}
+		}
+		off = body + size + size&1
+	}
+
+	if byteRate == 0 || dataSize < 0 {
+		return errors.New("no WAV fmt or data chunk found")
+	}
+	info.Duration = secondsDuration(float64(dataSize), float64(byteRate))
+	return nil
+}
+
+// parseWAVInfo reads the title (INAM) of a LIST INFO chunk.
+func parseWAVInfo(b []byte, info *MediaInfo) {
+	if len(b) < 4 || string(b[:4]) != "INFO" {
+		return
+	}
+	for b = b[4:]; len(b) >= 8; {
+		id := string(b[:4])
+		size := int(binary.LittleEndian.Uint32(b[4:]))
+		if size > len(b)-8 {
+			return
+		}
+		if id == "INAM" && len(info.Title) == 0 {
+			info.Title = strings.TrimRight(string(b[8:8+size]), "\x00")
+		}
+		b = b[8+size:]
+		if size&1 == 1 && len(b) > 0 {
+			b = b[1:]
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package podcast
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/georgboe/rss-feed-generator/html2text"
+	"github.com/georgboe/rss-feed-generator/parser"
+	"github.com/pkg/errors"
+)
+
+// ItemTransform changes an Item of a rewritten feed, and reports whether
+// the Item is kept.
+type ItemTransform func(i *Item) (bool, error)
+
+// ChannelTransform changes the Podcast of a rewritten feed, once all of
+// its Items went through the ItemTransforms.
+type ChannelTransform func(p *Podcast) error
+
+// Rewriter re-hosts feeds: it parses them, converts them with
+// NewFromFeed, runs them through a chain of transforms and writes them
+// back out with Encode.
+//
+// GUIDs are never rewritten, so that podcast apps do not download the
+// episodes of a rewritten feed again.
+type Rewriter struct {
+	Parser *parser.Parser
+
+	items   []ItemTransform
+	channel []ChannelTransform
+}
+
+// NewRewriter returns a Rewriter without any transforms.
+func NewRewriter() *Rewriter {
+	return &Rewriter{Parser: parser.NewParser()}
+}
+
+// AddItemTransform appends the transform to the chain run on each Item,
+// in order.  An Item that a transform drops is not seen by the next ones.
+func (r *Rewriter) AddItemTransform(t ItemTransform) {
+	if t == nil {
+		return
+	}
+	r.items = append(r.items, t)
+}
+
+// AddChannelTransform appends the transform to the chain run on the
+// Podcast, in order.
+func (r *Rewriter) AddChannelTransform(t ChannelTransform) {
+	if t == nil {
+		return
+	}
+	r.channel = append(r.channel, t)
+}
+
+// Transform converts the parsed feed and runs it through the transforms.
+// The Findings are those of NewFromFeed.
+func (r *Rewriter) Transform(feed *parser.Feed) (*Podcast, Findings, error) {
+	p, findings, err := NewFromFeed(feed)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "podcast.Transform")
+	}
+
+	items := p.Items[:0]
+	for n, i := range p.Items {
+		keep, err := r.transformItem(i)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "podcast.Transform: Items[%d]", n)
+		}
+		if keep {
+			items = append(items, i)
+		}
+	}
+	p.Items = items
+
+	for _, t := range r.channel {
+		if err := t(p); err != nil {
+			return nil, nil, errors.Wrap(err, "podcast.Transform")
+		}
+	}
+	return p, findings, nil
+}
+
+func (r *Rewriter) transformItem(i *Item) (bool, error) {
+	for _, t := range r.items {
+		keep, err := t(i)
+		if err != nil || !keep {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Rewrite parses the feed, transforms it and writes it to w.
+func (r *Rewriter) Rewrite(w io.Writer, feed io.Reader) (Findings, error) {
+	f, err := r.Parser.Parse(feed)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.Rewrite: r.Parser.Parse returned error")
+	}
+	p, findings, err := r.Transform(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "podcast.Rewrite")
+	}
+	if err := p.Encode(w); err != nil {
+		return nil, errors.Wrap(err, "podcast.Rewrite: p.Encode returned error")
+	}
+	return findings, nil
+}
+
+// RewriteEnclosureURLs replaces the URL of the enclosure and of the
+// alternate enclosure sources with rewrite(url).
+func RewriteEnclosureURLs(rewrite func(url string) string) ItemTransform {
+	return func(i *Item) (bool, error) {
+		if i.Enclosure != nil && len(i.Enclosure.URL) != 0 {
+			i.Enclosure.URL = rewrite(i.Enclosure.URL)
+		}
+		for _, ae := range i.PAlternateEnclosures {
+			for _, s := range ae.Sources {
+				if strings.HasPrefix(s.URI, "http://") || strings.HasPrefix(s.URI, "https://") {
+					s.URI = rewrite(s.URI)
+				}
+			}
+		}
+		return true, nil
+	}
+}
+
+// PrefixEnclosureURLs puts the analytics prefix in front of the enclosure
+// URLs, dropping their scheme as prefixes such as
+// "https://dts.podtrac.com/redirect.mp3/" expect.
+func PrefixEnclosureURLs(prefix string) ItemTransform {
+	return RewriteEnclosureURLs(func(url string) string {
+		if n := strings.Index(url, "://"); n >= 0 {
+			url = url[n+3:]
+		}
+		return prefix + url
+	})
+}
+
+// SetNewFeedURL points podcast apps to the re-hosted feed with
+// itunes:new-feed-url.
+func SetNewFeedURL(url string) ChannelTransform {
+	return func(p *Podcast) error {
+		p.AddNewFeedURL(url)
+		return nil
+	}
+}
+
+// FilterItems keeps the Items for which keep returns true.
+func FilterItems(keep func(i *Item) bool) ItemTransform {
+	return func(i *Item) (bool, error) {
+		return keep(i), nil
+	}
+}
+
+// FilterPublished keeps the Items published from from and before to.
+// A zero time leaves that end open, and Items without a valid pubDate
+// are dropped.
+func FilterPublished(from, to time.Time) ItemTransform {
+	return FilterItems(func(i *Item) bool {
+		t, err := parsePubDate(i.PubDate)
+		if err != nil {
+			return false
+		}
+		return (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to))
+	})
+}
+
+// FilterCategory keeps the Items in one of the categories, ignoring case.
+func FilterCategory(categories ...string) ItemTransform {
+	return FilterItems(func(i *Item) bool {
+		for _, ic := range i.Categories {
+			for _, c := range categories {
+				if strings.EqualFold(strings.TrimSpace(ic.Value), c) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// FilterTitle keeps the Items whose title matches re.
+func FilterTitle(re *regexp.Regexp) ItemTransform {
+	return FilterItems(func(i *Item) bool {
+		return re.MatchString(i.Title)
+	})
+}
+
+// TitleTemplate replaces the title of each Item with the output of tmpl,
+// which is executed with the Item, such as "{{.Title}} (ad-free)".
+func TitleTemplate(tmpl *template.Template) ItemTransform {
+	return func(i *Item) (bool, error) {
+		b := new(bytes.Buffer)
+		if err := tmpl.Execute(b, i); err != nil {
+			return false, errors.Wrap(err, "podcast.TitleTemplate: tmpl.Execute returned error")
+		}
+		i.Title = b.String()
+		return true, nil
+	}
+}
+
+// ChannelTitleTemplate replaces the title of the Podcast with the output
+// of tmpl, which is executed with the Podcast.
+func ChannelTitleTemplate(tmpl *template.Template) ChannelTransform {
+	return func(p *Podcast) error {
+		b := new(bytes.Buffer)
+		if err := tmpl.Execute(b, p); err != nil {
+			return errors.Wrap(err, "podcast.ChannelTitleTemplate: tmpl.Execute returned error")
+		}
+		p.Title = b.String()
+		return nil
+	}
+}
+
+// TruncateDescriptions shortens the description and iTunes summary of
+// each Item to at most max runes.  Longer ones lose their HTML, so that
+// no markup is cut in half.
+func TruncateDescriptions(max int) ItemTransform {
+	truncate := func(text string) string {
+		if utf8.RuneCountInString(text) <= max {
+			return text
+		}
+		return truncateRunes(html2text.HTML2Text(text), max)
+	}
+	return func(i *Item) (bool, error) {
+		if i.Description != nil {
+			i.Description.Text = truncate(i.Description.Text)
+		}
+		if i.ISummary != nil {
+			i.ISummary.Text = truncate(i.ISummary.Text)
+		}
+		return true, nil
+	}
+}
+
+// LimitItems keeps the first n Items of the feed, which are usually the
+// newest ones.
+func LimitItems(n int) ChannelTransform {
+	return func(p *Podcast) error {
+		if n >= 0 && len(p.Items) > n {
+			p.Items = p.Items[:n]
+		}
+		return nil
+	}
+}
@@ -0,0 +1,219 @@
+package podcast_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/georgboe/rss-feed-generator/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+const rewriteFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:media="http://search.yahoo.com/mrss/" xmlns:x="http://example.com/x">
+  <channel>
+    <title>Show</title>
+    <link>http://example.com/</link>
+    <description>A show</description>
+    <media:rating scheme="urn:simple">nonadult</media:rating>
+    <x:unknown>dropped</x:unknown>
+    <item>
+      <guid isPermaLink="false">ep-3</guid>
+      <title>Episode 3: Bonus</title>
+      <category>Bonus</category>
+      <description><![CDATA[<p>The <b>third</b> one</p>]]></description>
+      <pubDate>Wed, 03 Mar 2021 10:00:00 +0000</pubDate>
+      <enclosure url="https://cdn.example.com/3.mp3" length="300" type="audio/mpeg"/>
+      <media:content url="https://cdn.example.com/3.mp4" medium="video">
+        <media:title>Video</media:title>
+      </media:content>
+    </item>
+    <item>
+      <guid isPermaLink="false">ep-2</guid>
+      <title>Episode 2</title>
+      <category>Main</category>
+      <pubDate>Tue, 02 Mar 2021 10:00:00 +0000</pubDate>
+      <enclosure url="https://cdn.example.com/2.mp3" length="200" type="audio/mpeg"/>
+    </item>
+    <item>
+      <guid isPermaLink="false">ep-1</guid>
+      <title>Episode 1</title>
+      <category>main</category>
+      <pubDate>Mon, 01 Mar 2021 10:00:00 +0000</pubDate>
+      <enclosure url="http://cdn.example.com/1.mp3" length="100" type="audio/mpeg"/>
+    </item>
+  </channel>
+</rss>`
+
+func rewrite(t *testing.T, r *podcast.Rewriter) (*podcast.Podcast, string) {
+	feed, err := parser.NewParser().ParseString(rewriteFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _, err := r.Transform(feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, p.String()
+}
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	r := podcast.NewRewriter()
+	r.AddItemTransform(podcast.PrefixEnclosureURLs("https://dts.podtrac.com/redirect.mp3/"))
+	r.AddItemTransform(podcast.TitleTemplate(template.Must(template.New("").Parse("{{.Title}} (ad-free)"))))
+	r.AddChannelTransform(podcast.SetNewFeedURL("https://proxy.example.com/show.rss"))
+	r.AddChannelTransform(podcast.LimitItems(2))
+	var b bytes.Buffer
+
+	// act
+	findings, err := r.Rewrite(&b, strings.NewReader(rewriteFeed))
+
+	// assert
+	assert.NoError(t, err)
+	out := b.String()
+	assert.Contains(t, out, `<enclosure url="https://dts.podtrac.com/redirect.mp3/cdn.example.com/3.mp3" length="300" type="audio/mpeg">`)
+	assert.Contains(t, out, `<guid isPermaLink="false">ep-3</guid>`)
+	assert.Contains(t, out, `<title>Episode 2 (ad-free)</title>`)
+	assert.NotContains(t, out, "Episode 1")
+	assert.Contains(t, out, `<itunes:new-feed-url>https://proxy.example.com/show.rss</itunes:new-feed-url>`)
+	assert.Contains(t, out, `<media:rating xmlns:media="http://search.yahoo.com/mrss/" scheme="urn:simple">nonadult</media:rating>`)
+	assert.Contains(t, out, `<media:content xmlns:media="http://search.yahoo.com/mrss/" medium="video" url="https://cdn.example.com/3.mp4">`)
+	assert.Contains(t, out, `<media:title>Video</media:title>`)
+	assert.NotContains(t, out, "dropped")
+	assert.Equal(t, "Extensions.x.unknown[0]", findings[0].Field)
+}
+
+func TestRewriteFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		transform podcast.ItemTransform
+		titles    []string
+	}{
+		{"title", podcast.FilterTitle(regexp.MustCompile(`^Episode \d+$`)), []string{"Episode 2", "Episode 1"}},
+		{"category", podcast.FilterCategory("MAIN"), []string{"Episode 2", "Episode 1"}},
+		{"published", podcast.FilterPublished(time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 3, 10, 0, 0, 0, time.UTC)), []string{"Episode 2"}},
+		{"published since", podcast.FilterPublished(time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), time.Time{}), []string{"Episode 3: Bonus", "Episode 2"}},
+		{"items", podcast.FilterItems(func(i *podcast.Item) bool { return i.Enclosure.Length > 150 }), []string{"Episode 3: Bonus", "Episode 2"}},
+	}
+	for _, tt := range tests {
+		// arrange
+		r := podcast.NewRewriter()
+		r.AddItemTransform(tt.transform)
+
+		// act
+		p, _ := rewrite(t, r)
+
+		// assert
+		var titles []string
+		for _, i := range p.Items {
+			titles = append(titles, i.Title)
+		}
+		assert.Equal(t, tt.titles, titles, tt.name)
+	}
+}
+
+func TestRewriteTruncateDescriptions(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	r := podcast.NewRewriter()
+	r.AddItemTransform(podcast.TruncateDescriptions(9))
+	r.AddChannelTransform(podcast.ChannelTitleTemplate(template.Must(template.New("").Parse("{{.Title}} (mirror)"))))
+
+	// act
+	p, _ := rewrite(t, r)
+
+	// assert
+	assert.Equal(t, "The third", p.Items[0].Description.Text)
+	assert.Equal(t, "Show (mirror)", p.Title)
+}
+
+func TestRewriteTransformError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	r := podcast.NewRewriter()
+	r.AddItemTransform(podcast.TitleTemplate(template.Must(template.New("").Parse("{{.Missing}}"))))
+	var b bytes.Buffer
+
+	// act
+	_, err := r.Rewrite(&b, strings.NewReader(rewriteFeed))
+
+	// assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Items[0]")
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestRewriteParseError(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+
+	_, err := podcast.NewRewriter().Rewrite(&b, strings.NewReader("not a feed"))
+
+	assert.Error(t, err)
+}
+
+func TestExtensionElementUnknownNamespace(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.Extensions = append(p.Extensions, &podcast.ExtensionElement{Prefix: "unregistered"})
+	p.Extensions[0].Extension.Name = "tag"
+	var b bytes.Buffer
+
+	// act
+	err := p.Encode(&b)
+
+	// assert
+	assert.Error(t, err)
+}
+
+func TestRegisterExtensionNamespace(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := podcast.RegisterExtensionNamespace("rwtest", "http://example.com/rwtest")
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.Extensions = append(p.Extensions, &podcast.ExtensionElement{Prefix: "rwtest"})
+	p.Extensions[0].Extension.Name = "tag"
+	p.Extensions[0].Extension.Value = "a & b"
+
+	// act
+	out := p.String()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out, `<rwtest:tag xmlns:rwtest="http://example.com/rwtest">a &amp; b</rwtest:tag>`)
+	assert.Error(t, podcast.RegisterExtensionNamespace("", "http://example.com/"))
+}
+
+func TestExtensionElementNamespaceAttr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.Extensions = append(p.Extensions, &podcast.ExtensionElement{Prefix: "media"})
+	p.Extensions[0].Extension.Name = "content"
+	p.Extensions[0].Extension.Attrs = map[string]string{
+		"media": "http://search.yahoo.com/mrss/",
+		"url":   "http://example.com/a.mp4",
+	}
+
+	// act
+	out := p.String()
+
+	// assert
+	assert.Contains(t, out, `<media:content xmlns:media="http://search.yahoo.com/mrss/" url="http://example.com/a.mp4"></media:content>`)
+}
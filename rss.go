@@ -0,0 +1,81 @@
+package podcast
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Cloud protocols of the RSS 2.0 cloud element.
+const (
+	CloudXMLRPC   = "xml-rpc"
+	CloudSOAP     = "soap"
+	CloudHTTPPost = "http-post"
+)
+
+// Cloud lets processes register to be notified of updates to the channel.
+//
+// https://www.rssboard.org/rss-specification#ltcloudgtSubelementOfLtchannelgt
+type Cloud struct {
+	XMLName           xml.Name `xml:"cloud"`
+	Domain            string   `xml:"domain,attr"`
+	Port              int      `xml:"port,attr"`
+	Path              string   `xml:"path,attr"`
+	RegisterProcedure string   `xml:"registerProcedure,attr"`
+	Protocol          string   `xml:"protocol,attr"`
+}
+
+// SkipHours are the hours, from 0 to 23 in GMT, in which aggregators may
+// skip reading the feed.
+type SkipHours struct {
+	XMLName xml.Name `xml:"skipHours"`
+	Hours   []int    `xml:"hour"`
+}
+
+// SkipDays are the days, such as "Saturday", on which aggregators may
+// skip reading the feed.
+type SkipDays struct {
+	XMLName xml.Name `xml:"skipDays"`
+	Days    []string `xml:"day"`
+}
+
+// Category is an RSS 2.0 category of an Item, with the optional domain
+// of its taxonomy.
+type Category struct {
+	XMLName xml.Name `xml:"category"`
+	Domain  string   `xml:"domain,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+// Source is the RSS channel an Item came from.
+type Source struct {
+	XMLName xml.Name `xml:"source"`
+	URL     string   `xml:"url,attr"`
+	Title   string   `xml:",chardata"`
+}
+
+// skipDays are the valid days of SkipDays, in the order they are written.
+var skipDays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// skipDay returns the spelling of the day used by SkipDays, ignoring
+// case, and false when it is not a day of the week.
+func skipDay(day string) (string, bool) {
+	for _, d := range skipDays {
+		if strings.EqualFold(strings.TrimSpace(day), d) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+func validCloud(c *Cloud) bool {
+	switch c.Protocol {
+	case CloudXMLRPC, CloudSOAP, CloudHTTPPost:
+	default:
+		return false
+	}
+	return len(c.Domain) != 0 && len(c.Path) != 0 && c.Port > 0 && c.Port <= 65535
+}
+
+func validSkipHour(hour int) bool {
+	return hour >= 0 && hour <= 23
+}
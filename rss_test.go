@@ -0,0 +1,133 @@
+package podcast_test
+
+import (
+	"bytes"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/georgboe/rss-feed-generator/parser/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCloud(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	// act
+	err := p.AddCloud("rpc.example.com", 80, "/RPC2", "pingMe", podcast.CloudXMLRPC)
+	invalid := p.AddCloud("rpc.example.com", 0, "/RPC2", "pingMe", "carrier-pigeon")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Error(t, invalid)
+	assert.Equal(t, "rpc.example.com", p.Cloud.Domain)
+	assert.Equal(t, 80, p.Cloud.Port)
+	assert.Equal(t, podcast.CloudXMLRPC, p.Cloud.Protocol)
+}
+
+func TestAddSkipHoursAndDays(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	// act
+	hoursErr := p.AddSkipHours(23, 0, 5, 0)
+	badHourErr := p.AddSkipHours(2, 24)
+	daysErr := p.AddSkipDays(" saturday", "Sunday", "SATURDAY")
+	badDayErr := p.AddSkipDays("Someday")
+
+	// assert
+	assert.NoError(t, hoursErr)
+	assert.Error(t, badHourErr)
+	assert.NoError(t, daysErr)
+	assert.Error(t, badDayErr)
+	assert.Equal(t, []int{0, 5, 23}, p.SkipHours.Hours)
+	assert.Equal(t, []string{"Saturday", "Sunday"}, p.SkipDays.Days)
+}
+
+func TestItemAddCategorySourceComments(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	i := podcast.Item{}
+
+	// act
+	i.AddCategory("Tech", "http://example.com/taxonomy")
+	i.AddCategory("", "http://example.com/taxonomy")
+	i.AddCategory("News", "")
+	i.AddSource("Other Feed", "")
+	i.AddSource("Other Feed", "http://example.com/other.xml")
+	i.AddComments("")
+	i.AddComments("http://example.com/1#comments")
+
+	// assert
+	assert.Len(t, i.Categories, 2)
+	assert.Equal(t, "http://example.com/taxonomy", i.Categories[0].Domain)
+	assert.Equal(t, "News", i.Categories[1].Value)
+	assert.Equal(t, "http://example.com/other.xml", i.Source.URL)
+	assert.Equal(t, "Other Feed", i.Source.Title)
+	assert.Equal(t, "http://example.com/1#comments", i.Comments)
+}
+
+func TestValidateRSS(t *testing.T) {
+	t.Parallel()
+
+	valid := newValidPodcast(t)
+	p := newValidPodcast(t)
+	p.Cloud = &podcast.Cloud{Domain: "rpc.example.com", Port: 80, Path: "/RPC2", Protocol: "smtp"}
+	p.SkipHours = &podcast.SkipHours{Hours: []int{1, 24, 1}}
+	p.SkipDays = &podcast.SkipDays{Days: []string{"Monday", "monday", "Caturday"}}
+	p.Items[0].Source = &podcast.Source{Title: "Other Feed"}
+
+	findings := p.Validate(podcast.RSSProfile)
+
+	assert.Equal(t, []string{
+		"rss.cloud.valid",
+		"rss.skiphours.valid",
+		"rss.skiphours.valid",
+		"rss.skipdays.valid",
+		"rss.skipdays.valid",
+		"rss.item.source.url",
+	}, findingRules(findings))
+	assert.Equal(t, "SkipHours.Hours[1]", findings[1].Field)
+	assert.Equal(t, "Items[0].Source", findings[5].Field)
+	assert.Empty(t, valid.Validate(podcast.RSSProfile))
+}
+
+func TestEncodeRSSElements(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newValidPodcast(t)
+	assert.NoError(t, p.AddCloud("rpc.example.com", 80, "/RPC2", "pingMe", podcast.CloudXMLRPC))
+	assert.NoError(t, p.AddSkipHours(0, 1))
+	assert.NoError(t, p.AddSkipDays("Sunday"))
+	p.AddRating("(PICS-1.1 \"http://www.rsac.org/ratingsv01.html\" l r (n 0))")
+	p.Items[0].AddCategory("Tech", "http://example.com/taxonomy")
+	p.Items[0].AddSource("Other Feed", "http://example.com/other.xml")
+	p.Items[0].AddComments("http://example.com/1#comments")
+
+	// act
+	var b bytes.Buffer
+	err := p.Encode(&b)
+	out := b.String()
+	feed, parseErr := (&rss.Parser{}).Parse(&b)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out, `<cloud domain="rpc.example.com" port="80" path="/RPC2" registerProcedure="pingMe" protocol="xml-rpc"></cloud>`)
+	assert.Contains(t, out, `<skipHours>`+"\n"+`      <hour>0</hour>`)
+	assert.Contains(t, out, `<category domain="http://example.com/taxonomy">Tech</category>`)
+	assert.Contains(t, out, `<source url="http://example.com/other.xml">Other Feed</source>`)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, "80", feed.Cloud.Port)
+	assert.Equal(t, []string{"0", "1"}, feed.SkipHours)
+	assert.Equal(t, []string{"Sunday"}, feed.SkipDays)
+	assert.Equal(t, p.Rating, feed.Rating)
+	assert.Equal(t, "http://example.com/taxonomy", feed.Items[0].Categories[0].Domain)
+	assert.Equal(t, "Other Feed", feed.Items[0].Source.Title)
+	assert.Equal(t, "http://example.com/1#comments", feed.Items[0].Comments)
+}
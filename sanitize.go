@@ -0,0 +1,155 @@
+package podcast
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/georgboe/rss-feed-generator/parser/util"
+	"golang.org/x/text/unicode/norm"
+)
+
+// symbolReferences are the characters Apple asks to be written as
+// character references, see
+// https://help.apple.com/itc/podcasts_connect/#/itc1723472cb
+var symbolReferences = map[rune]string{
+	'©': "&#xA9;",
+	'℗': "&#x2117;",
+	'™': "&#x2122;",
+}
+
+var (
+	cdataOpen  = []byte("<![CDATA[")
+	cdataClose = []byte("]]>")
+)
+
+// sanitizeText strips the characters that are illegal in XML 1.0 and
+// normalises str to Unicode NFC.  It does not escape: encoding/xml does
+// that, exactly once, at encode time.
+func sanitizeText(str string) string {
+	if strings.IndexFunc(str, func(r rune) bool { return !util.IsXMLChar(r) }) >= 0 {
+		str = strings.Map(func(r rune) rune {
+			if r == utf8.RuneError || !util.IsXMLChar(r) {
+				return -1
+			}
+			return r
+		}, str)
+	}
+	return norm.NFC.String(str)
+}
+
+// sanitizedCopy returns a deep copy of v with sanitizeText applied to
+// every string that encoding/xml writes, following pointers, slices and
+// exported struct fields.  v itself is left as it is, so that encoding
+// does not change the caller's Podcast or Items.  Maps and interfaces,
+// which encoding/xml does not write, are shared with v.
+func sanitizedCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(sanitizedCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for n := 0; n < v.Len(); n++ {
+			c.Index(n).Set(sanitizedCopy(v.Index(n)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		t := v.Type()
+		for n := 0; n < v.NumField(); n++ {
+			if f := t.Field(n); f.PkgPath != "" || f.Tag.Get("xml") == "-" {
+				continue
+			}
+			c.Field(n).Set(sanitizedCopy(v.Field(n)))
+		}
+		return c
+	case reflect.String:
+		if s := v.String(); len(s) != 0 {
+			if clean := sanitizeText(s); clean != s {
+				return reflect.ValueOf(clean).Convert(v.Type())
+			}
+		}
+	}
+	return v
+}
+
+// encodable returns the sanitized copy of the Podcast that Encode
+// writes, with the descriptions sanitized with the HTMLPolicy and the
+// durations in the DurationFormat.
+func (p *Podcast) encodable() *Podcast {
+	c := sanitizedCopy(reflect.ValueOf(p)).Interface().(*Podcast)
+	if p.HTMLPolicy != nil && p.Description != nil {
+		text := p.Description.Text
+		if text == p.descriptionText {
+			text = p.descriptionHTML
+		}
+		c.Description = &Description{Text: sanitizeText(p.HTMLPolicy.Sanitize(text))}
+	}
+	for _, i := range c.Items {
+		p.formatDuration(i)
+		p.sanitizeHTML(i)
+	}
+	return c
+}
+
+// encodableItem returns the sanitized copy of the Item that EncodeStream
+// writes.
+func (p *Podcast) encodableItem(i *Item) *Item {
+	c := sanitizedCopy(reflect.ValueOf(i)).Interface().(*Item)
+	p.formatDuration(c)
+	p.sanitizeHTML(c)
+	return c
+}
+
+// escapeSymbols rewrites the symbolReferences in encoded XML as
+// character references, leaving the CDATA sections as they are.
+func escapeSymbols(b []byte) []byte {
+	var out []byte
+	copied, inCDATA := 0, false
+	for n := 0; n < len(b); {
+		switch {
+		case !inCDATA && bytes.HasPrefix(b[n:], cdataOpen):
+			inCDATA = true
+			n += len(cdataOpen)
+			continue
+		case inCDATA && bytes.HasPrefix(b[n:], cdataClose):
+			inCDATA = false
+			n += len(cdataClose)
+			continue
+		}
+		r, size := utf8.DecodeRune(b[n:])
+		if ref, ok := symbolReferences[r]; ok && !inCDATA {
+			out = append(append(out, b[copied:n]...), ref...)
+			copied = n + size
+		}
+		n += size
+	}
+	if out == nil {
+		return b
+	}
+	return append(out, b[copied:]...)
+}
+
+// sanitizeHTML sanitizes the description and content:encoded of the
+// copy of an Item being encoded with the HTMLPolicy of the Podcast.
+func (p *Podcast) sanitizeHTML(i *Item) {
+	if p.HTMLPolicy == nil {
+		return
+	}
+	if i.Description != nil {
+		i.Description = &Description{Text: sanitizeText(p.HTMLPolicy.Sanitize(i.Description.Text))}
+	}
+	if i.EncodedDescription != nil {
+		i.EncodedDescription = &EncodedContent{Text: sanitizeText(p.HTMLPolicy.Sanitize(i.EncodedDescription.Text))}
+	}
+}
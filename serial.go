@@ -0,0 +1,82 @@
+package podcast
+
+import (
+	"strconv"
+	"strings"
+)
+
+// episodeKey is the position of an Item within a show: its season, its
+// rank within the season and its episode number.
+type episodeKey struct {
+	season  int
+	rank    int
+	episode int
+}
+
+// Ranks of the Items within a season.  Trailers come first, then the
+// numbered episodes and last the unnumbered ones, such as most bonus
+// episodes.
+const (
+	rankTrailer = iota
+	rankNumbered
+	rankUnnumbered
+)
+
+// episodeNumber parses a SeasonNumber or EpisodeNumber, returning 0 when
+// it is empty and false when it is not a positive integer.
+func episodeNumber(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return 0, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func newEpisodeKey(i *Item) episodeKey {
+	season, _ := episodeNumber(i.SeasonNumber)
+	episode, _ := episodeNumber(i.EpisodeNumber)
+	k := episodeKey{season: season, rank: rankUnnumbered, episode: episode}
+	switch {
+	case i.EpisodeType == EpisodeTypeTrailer:
+		k.rank = rankTrailer
+	case episode != 0:
+		k.rank = rankNumbered
+	}
+	return k
+}
+
+func (k episodeKey) less(o episodeKey) bool {
+	if k.season != o.season {
+		return k.season < o.season
+	}
+	if k.rank != o.rank {
+		return k.rank < o.rank
+	}
+	return k.rank == rankNumbered && k.episode < o.episode
+}
+
+func validShowType(showType string) bool {
+	return showType == ShowTypeEpisodic || showType == ShowTypeSerial
+}
+
+// fullEpisodes returns the indexes of the full episodes with valid
+// numbers by season, in the order of the Items.
+func fullEpisodes(p *Podcast) map[int][]int {
+	bySeason := make(map[int][]int)
+	for n, i := range p.Items {
+		if i.EpisodeType != "" && i.EpisodeType != EpisodeTypeFull {
+			continue
+		}
+		season, ok := episodeNumber(i.SeasonNumber)
+		episode, valid := episodeNumber(i.EpisodeNumber)
+		if !ok || !valid || episode == 0 {
+			continue
+		}
+		bySeason[season] = append(bySeason[season], n)
+	}
+	return bySeason
+}
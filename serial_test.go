@@ -0,0 +1,151 @@
+package podcast_test
+
+import (
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSerialPodcast(t *testing.T, episodes ...podcast.Item) podcast.Podcast {
+	t.Helper()
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddItunesType(podcast.ShowTypeSerial)
+	for n := range episodes {
+		i := episodes[n]
+		i.AddEnclosure("http://example.com/"+i.Title+".mp3", podcast.MP3, "", 1)
+		_, err := p.AddItem(i)
+		assert.NoError(t, err)
+	}
+	return p
+}
+
+func newEpisode(t *testing.T, title string, season, episode int64, episodeType string) podcast.Item {
+	t.Helper()
+	i := podcast.Item{Title: title}
+	i.AddSeasonNumber(season)
+	i.AddEpisodeNumber(episode)
+	i.AddEpisodeType(episodeType)
+	return i
+}
+
+func itemTitles(p *podcast.Podcast) []string {
+	titles := []string{}
+	for _, i := range p.Items {
+		titles = append(titles, i.Title)
+	}
+	return titles
+}
+
+func TestAddItunesTypeInvalid(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddItunesType(podcast.ShowTypeSerial)
+
+	// act
+	p.AddItunesType("Serial")
+
+	// assert
+	assert.Equal(t, podcast.ShowTypeSerial, p.IType)
+}
+
+func TestOrderEpisodesSerial(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newSerialPodcast(t,
+		newEpisode(t, "s2e2", 2, 2, ""),
+		newEpisode(t, "bonus", 1, 0, podcast.EpisodeTypeBonus),
+		newEpisode(t, "s1e2", 1, 2, podcast.EpisodeTypeFull),
+		newEpisode(t, "s2-trailer", 2, 0, podcast.EpisodeTypeTrailer),
+		newEpisode(t, "s1e1", 1, 1, ""),
+		newEpisode(t, "s2e1", 2, 1, ""),
+	)
+
+	// act
+	findings, err := p.OrderEpisodes(true)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+	assert.Equal(t, []string{"s1e1", "s1e2", "bonus", "s2-trailer", "s2e1", "s2e2"}, itemTitles(&p))
+	assert.Equal(t, "1", p.Items[0].IOrder)
+	assert.Equal(t, "6", p.Items[5].IOrder)
+}
+
+func TestOrderEpisodesEpisodic(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newSerialPodcast(t,
+		newEpisode(t, "e1", 0, 1, ""),
+		newEpisode(t, "e3", 0, 3, ""),
+		newEpisode(t, "e2", 0, 2, ""),
+	)
+	p.AddItunesType(podcast.ShowTypeEpisodic)
+
+	// act
+	findings, err := p.OrderEpisodes(false)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+	assert.Equal(t, []string{"e3", "e2", "e1"}, itemTitles(&p))
+	assert.Empty(t, p.Items[0].IOrder)
+}
+
+func TestOrderEpisodesInconsistencies(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newSerialPodcast(t,
+		newEpisode(t, "s1-trailer", 1, 0, podcast.EpisodeTypeTrailer),
+		newEpisode(t, "s1-teaser", 1, 0, podcast.EpisodeTypeTrailer),
+		newEpisode(t, "s1e1", 1, 1, ""),
+		newEpisode(t, "s1e1-again", 1, 1, ""),
+		newEpisode(t, "s1e4", 1, 4, ""),
+		newEpisode(t, "s1-unnumbered", 1, 0, ""),
+		newEpisode(t, "s3e1", 3, 1, ""),
+	)
+	p.Items[6].EpisodeNumber = "one"
+
+	// act
+	findings, err := p.OrderEpisodes(false)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"serial.number.valid",
+		"serial.episode.required",
+		"serial.episode.duplicate",
+		"serial.episode.gap",
+		"serial.season.gap",
+		"serial.trailer.duplicate",
+	}, findingRules(findings))
+	assert.Equal(t, "Items[6].EpisodeNumber", findings[0].Field)
+	assert.Equal(t, "Items[5].EpisodeNumber", findings[1].Field)
+	assert.Equal(t, "Items[3].EpisodeNumber", findings[2].Field)
+	assert.Equal(t, "Items[4].EpisodeNumber", findings[3].Field)
+	assert.Equal(t, "Items[6].SeasonNumber", findings[4].Field)
+	assert.Equal(t, "Items[1].EpisodeType", findings[5].Field)
+	assert.True(t, findings.HasErrors())
+}
+
+func TestOrderEpisodesInvalidType(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newSerialPodcast(t, newEpisode(t, "e2", 0, 2, ""), newEpisode(t, "e1", 0, 1, ""))
+	p.IType = "daily"
+
+	// act
+	findings, err := p.OrderEpisodes(true)
+
+	// assert
+	assert.Error(t, err)
+	assert.Nil(t, findings)
+	assert.Equal(t, []string{"e2", "e1"}, itemTitles(&p))
+	assert.Equal(t, []string{"serial.type.valid"}, findingRules(p.Validate(podcast.SerialProfile)))
+}
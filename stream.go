@@ -0,0 +1,115 @@
+package podcast
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// channelClose is what Encode writes after the last Item of the channel.
+const channelClose = "\n  </channel>\n</rss>"
+
+// ItemIterator yields the Items of a streamed feed one at a time.
+//
+// Next returns io.EOF once there are no more Items.  Any other error
+// stops the stream and is returned by EncodeStream.
+type ItemIterator interface {
+	Next() (*Item, error)
+}
+
+// ItemIteratorFunc adapts an ordinary function to an ItemIterator.
+type ItemIteratorFunc func() (*Item, error)
+
+// Next calls f().
+func (f ItemIteratorFunc) Next() (*Item, error) {
+	return f()
+}
+
+// ItemsFromChannel returns an ItemIterator that receives Items from ch
+// until it is closed.
+func ItemsFromChannel(ch <-chan *Item) ItemIterator {
+	return ItemIteratorFunc(func() (*Item, error) {
+		i, ok := <-ch
+		if !ok {
+			return nil, io.EOF
+		}
+		return i, nil
+	})
+}
+
+// ItemsFromSlice returns an ItemIterator over items.
+func ItemsFromSlice(items []*Item) ItemIterator {
+	return ItemIteratorFunc(func() (*Item, error) {
+		if len(items) == 0 {
+			return nil, io.EOF
+		}
+		i := items[0]
+		items = items[1:]
+		return i, nil
+	})
+}
+
+// EncodeStream writes the bytes to the io.Writer stream in RSS 2.0
+// specification, pulling the Items from the iterator one at a time so
+// that only a single Item has to be held in memory.
+//
+// The channel and any Items already in p.Items are written first,
+// followed by the Items of the iterator.  The output is byte-identical
+// to Encode of a Podcast holding all of those Items.
+//
+// Items are written as-is, so prepare them as AddItem would, e.g. by
+// setting the enclosure type and iTunes fallbacks, before yielding them.
+// Their text is sanitized and escaped as Encode does.
+func (p *Podcast) EncodeStream(w io.Writer, items ItemIterator) error {
+	b := new(bytes.Buffer)
+	if err := p.Encode(b); err != nil {
+		return errors.Wrap(err, "podcast.EncodeStream: p.Encode returned error")
+	}
+	head := b.Bytes()
+	if !bytes.HasSuffix(head, []byte(channelClose)) {
+		return errors.New("podcast.EncodeStream: channel is not closed as expected")
+	}
+	if _, err := w.Write(head[:len(head)-len(channelClose)]); err != nil {
+		return errors.Wrap(err, "podcast.EncodeStream: w.Write return error")
+	}
+
+	// The channel Items are written at a depth of two by Encode, so the
+	// indentation is carried over by the prefix.  The encoder only writes
+	// the newline between elements, so the first one needs it by hand.
+	b.Reset()
+	e := xml.NewEncoder(b)
+	e.Indent("    ", "  ")
+	first := true
+	for {
+		i, err := items.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "podcast.EncodeStream: items.Next returned error")
+		}
+		if i == nil {
+			continue
+		}
+		if first {
+			if _, err := w.Write([]byte("\n")); err != nil {
+				return errors.Wrap(err, "podcast.EncodeStream: w.Write return error")
+			}
+			first = false
+		}
+		if err := e.Encode(p.encodableItem(i)); err != nil {
+			return errors.Wrap(err, "podcast.EncodeStream: e.Encode returned error")
+		}
+		if _, err := w.Write(escapeSymbols(b.Bytes())); err != nil {
+			return errors.Wrap(err, "podcast.EncodeStream: w.Write return error")
+		}
+		b.Reset()
+	}
+
+	if _, err := w.Write([]byte(channelClose)); err != nil {
+		return errors.Wrap(err, "podcast.EncodeStream: w.Write return error")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package podcast_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStreamItems(t *testing.T, n int) []*podcast.Item {
+	t.Helper()
+	items := make([]*podcast.Item, 0, n)
+	for x := 1; x <= n; x++ {
+		p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, nil, nil)
+		i := podcast.Item{
+			Title:       fmt.Sprintf("Episode %d", x),
+			Link:        fmt.Sprintf("http://example.com/%d.mp3", x),
+			Description: &podcast.Description{Text: "Description & <b>more</b>"},
+		}
+		i.AddEnclosure(i.Link, podcast.MP3, "", int64(x*1000))
+		i.SetPubDate(createdDate.AddDate(0, 0, x))
+		_, err := p.AddItem(i)
+		assert.NoError(t, err)
+		items = append(items, p.Items[0])
+	}
+	return items
+}
+
+func newStreamPodcast(t *testing.T) podcast.Podcast {
+	t.Helper()
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, &createdDate, &updatedDate)
+	p.AddAuthor([]string{"the name"})
+	p.AddCategory("Arts", []string{"Design"})
+	p.AddPodcastGUID("917393e3-1b1e-5cef-ace4-edaa54e1f810")
+	return p
+}
+
+func TestEncodeStreamMatchesEncode(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	items := newStreamItems(t, 5)
+	p := newStreamPodcast(t)
+	expected := newStreamPodcast(t)
+	expected.Items = items
+	want := new(bytes.Buffer)
+	assert.NoError(t, expected.Encode(want))
+	got := new(bytes.Buffer)
+
+	// act
+	err := p.EncodeStream(got, podcast.ItemsFromSlice(items))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, want.String(), got.String())
+}
+
+func TestEncodeStreamFromChannel(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	items := newStreamItems(t, 3)
+	p := newStreamPodcast(t)
+	p.Items = items[:1]
+	expected := newStreamPodcast(t)
+	expected.Items = items
+	want := new(bytes.Buffer)
+	assert.NoError(t, expected.Encode(want))
+	ch := make(chan *podcast.Item)
+	go func() {
+		for _, i := range items[1:] {
+			ch <- i
+		}
+		close(ch)
+	}()
+	got := new(bytes.Buffer)
+
+	// act
+	err := p.EncodeStream(got, podcast.ItemsFromChannel(ch))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, want.String(), got.String())
+}
+
+func TestEncodeStreamNoItems(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newStreamPodcast(t)
+	want := new(bytes.Buffer)
+	assert.NoError(t, p.Encode(want))
+	got := new(bytes.Buffer)
+
+	// act
+	err := p.EncodeStream(got, podcast.ItemsFromSlice(nil))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, want.String(), got.String())
+}
+
+func TestEncodeStreamIteratorError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newStreamPodcast(t)
+	items := podcast.ItemIteratorFunc(func() (*podcast.Item, error) {
+		return nil, errors.New("fake error")
+	})
+
+	// act
+	err := p.EncodeStream(ioutil.Discard, items)
+
+	// assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "fake error")
+}
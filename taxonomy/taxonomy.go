@@ -0,0 +1,333 @@
+// Package taxonomy is the Apple Podcasts category taxonomy, for use with
+// itunes:category.
+//
+// The feed must use the English names of the categories: the localized
+// names are for display only.  See
+// https://podcasters.apple.com/support/1691-apple-podcasts-categories
+package taxonomy
+
+import (
+	"html"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Version is the revision of the Apple Podcasts taxonomy held here.
+const Version = "2019"
+
+// ErrUnknownCategory is the cause of the errors of Lookup and Parse.
+var ErrUnknownCategory = errors.New("unknown category")
+
+// Category is a category or subcategory, named as Apple spells it.
+type Category string
+
+// Categories, each followed by its subcategories.
+const (
+	Arts               Category = "Arts"
+	ArtsBooks          Category = "Books"
+	ArtsDesign         Category = "Design"
+	ArtsFashionBeauty  Category = "Fashion & Beauty"
+	ArtsFood           Category = "Food"
+	ArtsPerformingArts Category = "Performing Arts"
+	ArtsVisualArts     Category = "Visual Arts"
+
+	Business                 Category = "Business"
+	BusinessCareers          Category = "Careers"
+	BusinessEntrepreneurship Category = "Entrepreneurship"
+	BusinessInvesting        Category = "Investing"
+	BusinessManagement       Category = "Management"
+	BusinessMarketing        Category = "Marketing"
+	BusinessNonProfit        Category = "Non-Profit"
+
+	Comedy           Category = "Comedy"
+	ComedyInterviews Category = "Comedy Interviews"
+	ComedyImprov     Category = "Improv"
+	ComedyStandUp    Category = "Stand-Up"
+
+	Education                 Category = "Education"
+	EducationCourses          Category = "Courses"
+	EducationHowTo            Category = "How To"
+	EducationLanguageLearning Category = "Language Learning"
+	EducationSelfImprovement  Category = "Self-Improvement"
+
+	Fiction               Category = "Fiction"
+	FictionComedyFiction  Category = "Comedy Fiction"
+	FictionDrama          Category = "Drama"
+	FictionScienceFiction Category = "Science Fiction"
+
+	Government Category = "Government"
+
+	History Category = "History"
+
+	HealthFitness                  Category = "Health & Fitness"
+	HealthFitnessAlternativeHealth Category = "Alternative Health"
+	HealthFitnessFitness           Category = "Fitness"
+	HealthFitnessMedicine          Category = "Medicine"
+	HealthFitnessMentalHealth      Category = "Mental Health"
+	HealthFitnessNutrition         Category = "Nutrition"
+	HealthFitnessSexuality         Category = "Sexuality"
+
+	KidsFamily                 Category = "Kids & Family"
+	KidsFamilyEducationForKids Category = "Education for Kids"
+	KidsFamilyParenting        Category = "Parenting"
+	KidsFamilyPetsAnimals      Category = "Pets & Animals"
+	KidsFamilyStoriesForKids   Category = "Stories for Kids"
+
+	Leisure               Category = "Leisure"
+	LeisureAnimationManga Category = "Animation & Manga"
+	LeisureAutomotive     Category = "Automotive"
+	LeisureAviation       Category = "Aviation"
+	LeisureCrafts         Category = "Crafts"
+	LeisureGames          Category = "Games"
+	LeisureHobbies        Category = "Hobbies"
+	LeisureHomeGarden     Category = "Home & Garden"
+	LeisureVideoGames     Category = "Video Games"
+
+	Music           Category = "Music"
+	MusicCommentary Category = "Music Commentary"
+	MusicHistory    Category = "Music History"
+	MusicInterviews Category = "Music Interviews"
+
+	News                  Category = "News"
+	NewsBusinessNews      Category = "Business News"
+	NewsDailyNews         Category = "Daily News"
+	NewsEntertainmentNews Category = "Entertainment News"
+	NewsCommentary        Category = "News Commentary"
+	NewsPolitics          Category = "Politics"
+	NewsSportsNews        Category = "Sports News"
+	NewsTechNews          Category = "Tech News"
+
+	ReligionSpirituality             Category = "Religion & Spirituality"
+	ReligionSpiritualityBuddhism     Category = "Buddhism"
+	ReligionSpiritualityChristianity Category = "Christianity"
+	ReligionSpiritualityHinduism     Category = "Hinduism"
+	ReligionSpiritualityIslam        Category = "Islam"
+	ReligionSpiritualityJudaism      Category = "Judaism"
+	ReligionSpiritualityReligion     Category = "Religion"
+	ReligionSpiritualitySpirituality Category = "Spirituality"
+
+	Science                Category = "Science"
+	ScienceAstronomy       Category = "Astronomy"
+	ScienceChemistry       Category = "Chemistry"
+	ScienceEarthSciences   Category = "Earth Sciences"
+	ScienceLifeSciences    Category = "Life Sciences"
+	ScienceMathematics     Category = "Mathematics"
+	ScienceNaturalSciences Category = "Natural Sciences"
+	ScienceNature          Category = "Nature"
+	SciencePhysics         Category = "Physics"
+	ScienceSocialSciences  Category = "Social Sciences"
+
+	SocietyCulture                 Category = "Society & Culture"
+	SocietyCultureDocumentary      Category = "Documentary"
+	SocietyCulturePersonalJournals Category = "Personal Journals"
+	SocietyCulturePhilosophy       Category = "Philosophy"
+	SocietyCulturePlacesTravel     Category = "Places & Travel"
+	SocietyCultureRelationships    Category = "Relationships"
+
+	Sports              Category = "Sports"
+	SportsBaseball      Category = "Baseball"
+	SportsBasketball    Category = "Basketball"
+	SportsCricket       Category = "Cricket"
+	SportsFantasySports Category = "Fantasy Sports"
+	SportsFootball      Category = "Football"
+	SportsGolf          Category = "Golf"
+	SportsHockey        Category = "Hockey"
+	SportsRugby         Category = "Rugby"
+	SportsRunning       Category = "Running"
+	SportsSoccer        Category = "Soccer"
+	SportsSwimming      Category = "Swimming"
+	SportsTennis        Category = "Tennis"
+	SportsVolleyball    Category = "Volleyball"
+	SportsWilderness    Category = "Wilderness"
+	SportsWrestling     Category = "Wrestling"
+
+	Technology Category = "Technology"
+
+	TrueCrime Category = "True Crime"
+
+	TVFilm               Category = "TV & Film"
+	TVFilmAfterShows     Category = "After Shows"
+	TVFilmFilmHistory    Category = "Film History"
+	TVFilmFilmInterviews Category = "Film Interviews"
+	TVFilmFilmReviews    Category = "Film Reviews"
+	TVFilmTVReviews      Category = "TV Reviews"
+)
+
+// tree lists the categories in the order Apple does.
+var tree = []struct {
+	category      Category
+	subcategories []Category
+}{
+	{Arts, []Category{ArtsBooks, ArtsDesign, ArtsFashionBeauty, ArtsFood, ArtsPerformingArts, ArtsVisualArts}},
+	{Business, []Category{BusinessCareers, BusinessEntrepreneurship, BusinessInvesting, BusinessManagement, BusinessMarketing, BusinessNonProfit}},
+	{Comedy, []Category{ComedyInterviews, ComedyImprov, ComedyStandUp}},
+	{Education, []Category{EducationCourses, EducationHowTo, EducationLanguageLearning, EducationSelfImprovement}},
+	{Fiction, []Category{FictionComedyFiction, FictionDrama, FictionScienceFiction}},
+	{Government, []Category{}},
+	{History, []Category{}},
+	{HealthFitness, []Category{HealthFitnessAlternativeHealth, HealthFitnessFitness, HealthFitnessMedicine, HealthFitnessMentalHealth, HealthFitnessNutrition, HealthFitnessSexuality}},
+	{KidsFamily, []Category{KidsFamilyEducationForKids, KidsFamilyParenting, KidsFamilyPetsAnimals, KidsFamilyStoriesForKids}},
+	{Leisure, []Category{LeisureAnimationManga, LeisureAutomotive, LeisureAviation, LeisureCrafts, LeisureGames, LeisureHobbies, LeisureHomeGarden, LeisureVideoGames}},
+	{Music, []Category{MusicCommentary, MusicHistory, MusicInterviews}},
+	{News, []Category{NewsBusinessNews, NewsDailyNews, NewsEntertainmentNews, NewsCommentary, NewsPolitics, NewsSportsNews, NewsTechNews}},
+	{ReligionSpirituality, []Category{ReligionSpiritualityBuddhism, ReligionSpiritualityChristianity, ReligionSpiritualityHinduism, ReligionSpiritualityIslam, ReligionSpiritualityJudaism, ReligionSpiritualityReligion, ReligionSpiritualitySpirituality}},
+	{Science, []Category{ScienceAstronomy, ScienceChemistry, ScienceEarthSciences, ScienceLifeSciences, ScienceMathematics, ScienceNaturalSciences, ScienceNature, SciencePhysics, ScienceSocialSciences}},
+	{SocietyCulture, []Category{SocietyCultureDocumentary, SocietyCulturePersonalJournals, SocietyCulturePhilosophy, SocietyCulturePlacesTravel, SocietyCultureRelationships}},
+	{Sports, []Category{SportsBaseball, SportsBasketball, SportsCricket, SportsFantasySports, SportsFootball, SportsGolf, SportsHockey, SportsRugby, SportsRunning, SportsSoccer, SportsSwimming, SportsTennis, SportsVolleyball, SportsWilderness, SportsWrestling}},
+	{Technology, []Category{}},
+	{TrueCrime, []Category{}},
+	{TVFilm, []Category{TVFilmAfterShows, TVFilmFilmHistory, TVFilmFilmInterviews, TVFilmFilmReviews, TVFilmTVReviews}},
+}
+
+var (
+	parents = map[Category]Category{}
+	byKey   = map[string]Category{}
+
+	localizedMu sync.RWMutex
+	localized   = map[string]map[Category]string{}
+)
+
+func init() {
+	for _, t := range tree {
+		parents[t.category] = ""
+		byKey[key(string(t.category))] = t.category
+		for _, sub := range t.subcategories {
+			parents[sub] = t.category
+			byKey[key(string(sub))] = sub
+		}
+	}
+}
+
+// key folds name for Lookup: entities are decoded, case and spacing are
+// ignored, a hyphen is a space and "and" is the same as "&".
+func key(name string) string {
+	name = strings.ToLower(html.UnescapeString(name))
+	name = strings.NewReplacer("&", " & ", "-", " ").Replace(name)
+	words := strings.Fields(name)
+	for n, w := range words {
+		if w == "and" {
+			words[n] = "&"
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Lookup returns the Category named name, ignoring case, spacing and
+// whether the ampersand is written as "&", "&amp;" or "and".
+func Lookup(name string) (Category, error) {
+	c, ok := byKey[key(name)]
+	if !ok {
+		return "", errors.Wrapf(ErrUnknownCategory, "taxonomy.Lookup: %q", name)
+	}
+	return c, nil
+}
+
+// Parse looks up each of the names, and groups them by parent in the
+// order they are first seen.  A subcategory brings its parent along.
+func Parse(names []string) ([]Category, map[Category][]Category, error) {
+	var order []Category
+	children := make(map[Category][]Category)
+	for _, name := range names {
+		c, err := Lookup(name)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "taxonomy.Parse")
+		}
+		parent := c.Parent()
+		if len(parent) == 0 {
+			parent = c
+		}
+		if _, ok := children[parent]; !ok {
+			order = append(order, parent)
+			children[parent] = []Category{}
+		}
+		if c != parent && !contains(children[parent], c) {
+			children[parent] = append(children[parent], c)
+		}
+	}
+	return order, children, nil
+}
+
+func contains(categories []Category, c Category) bool {
+	for _, each := range categories {
+		if each == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Categories returns the top-level categories.
+func Categories() []Category {
+	categories := make([]Category, len(tree))
+	for n, t := range tree {
+		categories[n] = t.category
+	}
+	return categories
+}
+
+// String returns the name of the Category.
+func (c Category) String() string {
+	return string(c)
+}
+
+// Valid reports whether the Category is in the taxonomy.
+func (c Category) Valid() bool {
+	_, ok := parents[c]
+	return ok
+}
+
+// Parent returns the parent of a subcategory, or "" for a top-level or
+// unknown Category.
+func (c Category) Parent() Category {
+	return parents[c]
+}
+
+// Subcategories returns the subcategories of a top-level Category.
+func (c Category) Subcategories() []Category {
+	for _, t := range tree {
+		if t.category == c {
+			return append([]Category{}, t.subcategories...)
+		}
+	}
+	return nil
+}
+
+// RegisterLocalizedNames sets the display names of categories in the
+// language, such as "de" or "fr-CA", replacing those already set.
+func RegisterLocalizedNames(lang string, names map[Category]string) {
+	lang = langKey(lang)
+	localizedMu.Lock()
+	defer localizedMu.Unlock()
+	if localized[lang] == nil {
+		localized[lang] = make(map[Category]string)
+	}
+	for c, name := range names {
+		localized[lang][c] = name
+	}
+}
+
+// LocalizedName returns the display name of the Category in the
+// language, falling back from "fr-CA" to "fr" and then to the English
+// name.
+func (c Category) LocalizedName(lang string) string {
+	lang = langKey(lang)
+	localizedMu.RLock()
+	defer localizedMu.RUnlock()
+	for {
+		if name, ok := localized[lang][c]; ok {
+			return name
+		}
+		n := strings.LastIndex(lang, "-")
+		if n < 0 {
+			return string(c)
+		}
+		lang = lang[:n]
+	}
+}
+
+// langKey folds the language tag, so that "fr_CA" is the same as "fr-ca".
+func langKey(lang string) string {
+	return strings.Replace(strings.ToLower(lang), "_", "-", -1)
+}
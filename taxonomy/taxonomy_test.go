@@ -0,0 +1,110 @@
+package taxonomy_test
+
+import (
+	"testing"
+
+	"github.com/georgboe/rss-feed-generator/taxonomy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want taxonomy.Category
+	}{
+		{"Arts", taxonomy.Arts},
+		{"kids & family", taxonomy.KidsFamily},
+		{"Kids &amp; Family", taxonomy.KidsFamily},
+		{"KIDS AND FAMILY", taxonomy.KidsFamily},
+		{"  Religion  and Spirituality ", taxonomy.ReligionSpirituality},
+		{"tv&film", taxonomy.TVFilm},
+		{"stand up", taxonomy.ComedyStandUp},
+		{"Tech News", taxonomy.NewsTechNews},
+	}
+	for _, tt := range tests {
+		c, err := taxonomy.Lookup(tt.name)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, c, tt.name)
+	}
+}
+
+func TestLookupUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := taxonomy.Lookup("Games & Hobbies")
+
+	assert.Error(t, err)
+	assert.Equal(t, taxonomy.ErrUnknownCategory, errors.Cause(err))
+}
+
+func TestCategoryParent(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, taxonomy.Arts, taxonomy.ArtsBooks.Parent())
+	assert.Equal(t, taxonomy.Category(""), taxonomy.Arts.Parent())
+	assert.True(t, taxonomy.ArtsBooks.Valid())
+	assert.False(t, taxonomy.Category("Books & Comics").Valid())
+	assert.Equal(t, []taxonomy.Category{taxonomy.ComedyInterviews, taxonomy.ComedyImprov, taxonomy.ComedyStandUp}, taxonomy.Comedy.Subcategories())
+	assert.Nil(t, taxonomy.ArtsBooks.Subcategories())
+}
+
+func TestCategories(t *testing.T) {
+	t.Parallel()
+
+	categories := taxonomy.Categories()
+
+	assert.Len(t, categories, 19)
+	for _, c := range categories {
+		assert.True(t, c.Valid(), c.String())
+		assert.Len(t, c.Parent(), 0, c.String())
+		for _, sub := range c.Subcategories() {
+			assert.Equal(t, c, sub.Parent(), sub.String())
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	// act
+	order, children, err := taxonomy.Parse([]string{"Documentary", "arts", "Books", "Design", "Books", "Society and Culture"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []taxonomy.Category{taxonomy.SocietyCulture, taxonomy.Arts}, order)
+	assert.Equal(t, map[taxonomy.Category][]taxonomy.Category{
+		taxonomy.SocietyCulture: {taxonomy.SocietyCultureDocumentary},
+		taxonomy.Arts:           {taxonomy.ArtsBooks, taxonomy.ArtsDesign},
+	}, children)
+}
+
+func TestParseUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := taxonomy.Parse([]string{"Arts", "Podcasting"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"Podcasting"`)
+}
+
+func TestLocalizedName(t *testing.T) {
+	// arrange
+	taxonomy.RegisterLocalizedNames("de", map[taxonomy.Category]string{
+		taxonomy.Arts:      "Kunst",
+		taxonomy.ArtsBooks: "Bücher",
+	})
+	taxonomy.RegisterLocalizedNames("de-AT", map[taxonomy.Category]string{
+		taxonomy.ArtsBooks: "Buecher",
+	})
+
+	// assert
+	assert.Equal(t, "Kunst", taxonomy.Arts.LocalizedName("de"))
+	assert.Equal(t, "Kunst", taxonomy.Arts.LocalizedName("de-AT"))
+	assert.Equal(t, "Buecher", taxonomy.ArtsBooks.LocalizedName("de_at"))
+	assert.Equal(t, "Design", taxonomy.ArtsDesign.LocalizedName("de"))
+	assert.Equal(t, "Arts", taxonomy.Arts.LocalizedName("fr"))
+	assert.Equal(t, "Arts", taxonomy.Arts.LocalizedName(""))
+}
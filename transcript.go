@@ -0,0 +1,220 @@
+package podcast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: https://github.com/Podcastindex-org/podcast-namespace/blob/main/transcripts/transcripts.md
+//
+
+// Transcript formats written by Transcript.Encode.  WebVTT and SRT are
+// caption-grade formats, timed closely enough to be shown as captions.
+const (
+	TranscriptVTT  = "text/vtt"
+	TranscriptSRT  = "application/x-subrip"
+	TranscriptJSON = "application/json"
+	TranscriptHTML = "text/html"
+
+	// TranscriptJSONVersion is the version of the JSON transcript format.
+	TranscriptJSONVersion = "1.0.0"
+)
+
+// Transcript is the timed text of an episode in a single language.
+type Transcript struct {
+	Language string
+	Segments []*TranscriptSegment
+}
+
+// TranscriptSegment is a single timed passage of the Transcript.
+//
+// Start and End are expressed in seconds from the start of the episode.
+// Speaker is optional.
+type TranscriptSegment struct {
+	Start   float64
+	End     float64
+	Speaker string
+	Body    string
+}
+
+type transcriptJSON struct {
+	Version  string                   `json:"version"`
+	Segments []*transcriptSegmentJSON `json:"segments"`
+}
+
+type transcriptSegmentJSON struct {
+	Speaker   string  `json:"speaker,omitempty"`
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime"`
+	Body      string  `json:"body"`
+}
+
+// NewTranscript instantiates a Transcript in the language, such as "en".
+func NewTranscript(language string) Transcript {
+	return Transcript{Language: language}
+}
+
+// AddSegment adds a timed passage to the Transcript.
+// Calling this method multiple times will APPEND the segment.
+//
+// The segment needs a body, a start that is not negative and an end
+// after the start.
+func (t *Transcript) AddSegment(segment TranscriptSegment) error {
+	if len(strings.TrimSpace(segment.Body)) == 0 {
+		return errors.New("transcript.AddSegment: Body is required")
+	}
+	if segment.Start < 0 || segment.End <= segment.Start {
+		return errors.Errorf("transcript.AddSegment: %v to %v is not a valid time range", segment.Start, segment.End)
+	}
+
+	t.Segments = append(t.Segments, &segment)
+	return nil
+}
+
+// Encode writes the bytes of the Transcript to the io.Writer stream in
+// the format of mimeType, one of TranscriptVTT, TranscriptSRT,
+// TranscriptJSON or TranscriptHTML.  Segments are ordered by start time.
+func (t *Transcript) Encode(w io.Writer, mimeType string) error {
+	b := new(bytes.Buffer)
+	switch mimeType {
+	case TranscriptVTT:
+		t.writeVTT(b)
+	case TranscriptSRT:
+		t.writeSRT(b)
+	case TranscriptJSON:
+		if err := t.writeJSON(b); err != nil {
+			return errors.Wrap(err, "transcript.Encode: writeJSON returned error")
+		}
+	case TranscriptHTML:
+		t.writeHTML(b)
+	default:
+		return errors.New("transcript.Encode: unsupported format " + mimeType)
+	}
+
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return errors.Wrap(err, "transcript.Encode: w.Write return error")
+	}
+	return nil
+}
+
+// AttachTo links the Transcript, hosted at url in the format of mimeType,
+// to the Item as a podcast:transcript in the Transcript language.
+//
+// Caption-grade formats are attached with rel="captions" and mark the
+// Item as closed captioned (IIsClosedCaptioned).
+func (t *Transcript) AttachTo(i *Item, url, mimeType string) error {
+	if len(url) == 0 {
+		return errors.New("transcript.AttachTo: url is required")
+	}
+	var rel string
+	switch mimeType {
+	case TranscriptVTT, TranscriptSRT:
+		rel = "captions"
+	case TranscriptJSON, TranscriptHTML:
+	default:
+		return errors.New("transcript.AttachTo: unsupported format " + mimeType)
+	}
+
+	i.AddTranscript(url, mimeType, t.Language, rel)
+	if len(rel) != 0 {
+		i.IIsClosedCaptioned = "Yes"
+	}
+	return nil
+}
+
+// sortedSegments returns the segments ordered by start time, keeping the
+// order in which they were added for equal times.
+func (t *Transcript) sortedSegments() []*TranscriptSegment {
+	segments := append([]*TranscriptSegment{}, t.Segments...)
+	sort.SliceStable(segments, func(a, b int) bool {
+		return segments[a].Start < segments[b].Start
+	})
+	return segments
+}
+
+func (t *Transcript) writeVTT(b *bytes.Buffer) {
+	b.WriteString("WEBVTT\n")
+	for _, s := range t.sortedSegments() {
+		fmt.Fprintf(b, "\n%s --> %s\n", formatNormalPlayTime(s.Start), formatNormalPlayTime(s.End))
+		if len(s.Speaker) != 0 {
+			fmt.Fprintf(b, "<v %s>", vttEscape(s.Speaker))
+		}
+		b.WriteString(vttEscape(cueText(s.Body)))
+		b.WriteString("\n")
+	}
+}
+
+func (t *Transcript) writeSRT(b *bytes.Buffer) {
+	for n, s := range t.sortedSegments() {
+		if n > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%d\n%s --> %s\n", n+1,
+			strings.Replace(formatNormalPlayTime(s.Start), ".", ",", 1),
+			strings.Replace(formatNormalPlayTime(s.End), ".", ",", 1))
+		if len(s.Speaker) != 0 {
+			b.WriteString(s.Speaker + ": ")
+		}
+		b.WriteString(cueText(s.Body))
+		b.WriteString("\n")
+	}
+}
+
+func (t *Transcript) writeJSON(b *bytes.Buffer) error {
+	doc := transcriptJSON{
+		Version:  TranscriptJSONVersion,
+		Segments: []*transcriptSegmentJSON{},
+	}
+	for _, s := range t.sortedSegments() {
+		doc.Segments = append(doc.Segments, &transcriptSegmentJSON{
+			Speaker:   s.Speaker,
+			StartTime: s.Start,
+			EndTime:   s.End,
+			Body:      s.Body,
+		})
+	}
+
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	return e.Encode(doc)
+}
+
+// writeHTML writes the transcript as the plain HTML of the Podcast Index
+// specification, naming the speaker whenever it changes.
+func (t *Transcript) writeHTML(b *bytes.Buffer) {
+	var speaker string
+	for _, s := range t.sortedSegments() {
+		if len(s.Speaker) != 0 && s.Speaker != speaker {
+			fmt.Fprintf(b, "<cite>%s:</cite>\n", html.EscapeString(s.Speaker))
+		}
+		speaker = s.Speaker
+		fmt.Fprintf(b, "<time>%s</time>\n", parseDuration(int64(s.Start)))
+		fmt.Fprintf(b, "<p>%s</p>\n", html.EscapeString(strings.TrimSpace(s.Body)))
+	}
+}
+
+// cueText trims the body of a caption cue and drops its blank lines,
+// which would otherwise end the cue early.
+func cueText(body string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.Replace(body, "\r\n", "\n", -1), "\n") {
+		if line = strings.TrimSpace(line); len(line) != 0 {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func vttEscape(str string) string {
+	return vttEscaper.Replace(str)
+}
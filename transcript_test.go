@@ -0,0 +1,207 @@
+package podcast_test
+
+import (
+	"bytes"
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTranscript(t *testing.T) podcast.Transcript {
+	t.Helper()
+	tr := podcast.NewTranscript("en")
+	for _, s := range []podcast.TranscriptSegment{
+		{Start: 3.2, End: 5.75, Speaker: "Bob", Body: "Thanks <Alice> & welcome."},
+		{Start: 0, End: 3.2, Speaker: "Alice", Body: "Hello and welcome\n\nto the show."},
+		{Start: 65, End: 66.5, Speaker: "Bob", Body: "Let's begin."},
+	} {
+		if err := tr.AddSegment(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tr
+}
+
+func encodeTranscript(t *testing.T, tr podcast.Transcript, mimeType string) string {
+	var b bytes.Buffer
+	if err := tr.Encode(&b, mimeType); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestTranscriptEncodeVTT(t *testing.T) {
+	t.Parallel()
+
+	tr := newTranscript(t)
+
+	out := encodeTranscript(t, tr, podcast.TranscriptVTT)
+
+	assert.Equal(t, `WEBVTT
+
+00:00:00.000 --> 00:00:03.200
+<v Alice>Hello and welcome
+to the show.
+
+00:00:03.200 --> 00:00:05.750
+<v Bob>Thanks &lt;Alice&gt; &amp; welcome.
+
+00:01:05.000 --> 00:01:06.500
+<v Bob>Let's begin.
+`, out)
+}
+
+func TestTranscriptEncodeSRT(t *testing.T) {
+	t.Parallel()
+
+	tr := newTranscript(t)
+
+	out := encodeTranscript(t, tr, podcast.TranscriptSRT)
+
+	assert.Equal(t, `1
+00:00:00,000 --> 00:00:03,200
+Alice: Hello and welcome
+to the show.
+
+2
+00:00:03,200 --> 00:00:05,750
+Bob: Thanks <Alice> & welcome.
+
+3
+00:01:05,000 --> 00:01:06,500
+Bob: Let's begin.
+`, out)
+}
+
+func TestTranscriptEncodeJSON(t *testing.T) {
+	t.Parallel()
+
+	tr := podcast.NewTranscript("en")
+	assert.NoError(t, tr.AddSegment(podcast.TranscriptSegment{Start: 0, End: 1.5, Speaker: "Alice", Body: "Hi & bye"}))
+	assert.NoError(t, tr.AddSegment(podcast.TranscriptSegment{Start: 1.5, End: 2, Body: "Music"}))
+
+	out := encodeTranscript(t, tr, podcast.TranscriptJSON)
+
+	assert.Equal(t, `{
+  "version": "1.0.0",
+  "segments": [
+    {
+      "speaker": "Alice",
+      "startTime": 0,
+      "endTime": 1.5,
+      "body": "Hi & bye"
+    },
+    {
+      "startTime": 1.5,
+      "endTime": 2,
+      "body": "Music"
+    }
+  ]
+}
+`, out)
+}
+
+func TestTranscriptEncodeHTML(t *testing.T) {
+	t.Parallel()
+
+	tr := newTranscript(t)
+
+	out := encodeTranscript(t, tr, podcast.TranscriptHTML)
+
+	assert.Equal(t, `<cite>Alice:</cite>
+<time>0:00</time>
+<p>Hello and welcome
+
+to the show.</p>
+<cite>Bob:</cite>
+<time>0:03</time>
+<p>Thanks &lt;Alice&gt; &amp; welcome.</p>
+<time>1:05</time>
+<p>Let&#39;s begin.</p>
+`, out)
+}
+
+func TestTranscriptEncodeUnsupported(t *testing.T) {
+	t.Parallel()
+
+	tr := newTranscript(t)
+
+	err := tr.Encode(&bytes.Buffer{}, "text/plain")
+
+	assert.Error(t, err)
+}
+
+func TestTranscriptAddSegmentInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		segment podcast.TranscriptSegment
+	}{
+		{"no body", podcast.TranscriptSegment{Start: 0, End: 1, Body: "  "}},
+		{"negative start", podcast.TranscriptSegment{Start: -1, End: 1, Body: "Hi"}},
+		{"end before start", podcast.TranscriptSegment{Start: 2, End: 1, Body: "Hi"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tr := podcast.NewTranscript("en")
+
+			err := tr.AddSegment(tt.segment)
+
+			assert.Error(t, err)
+			assert.Len(t, tr.Segments, 0)
+		})
+	}
+}
+
+func TestTranscriptAttachToCaptions(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tr := newTranscript(t)
+	i := podcast.Item{}
+
+	// act
+	err := tr.AttachTo(&i, "http://example.com/1.vtt", podcast.TranscriptVTT)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, i.PTranscripts, 1)
+	assert.Equal(t, "http://example.com/1.vtt", i.PTranscripts[0].URL)
+	assert.Equal(t, "text/vtt", i.PTranscripts[0].Type)
+	assert.Equal(t, "en", i.PTranscripts[0].Language)
+	assert.Equal(t, "captions", i.PTranscripts[0].Rel)
+	assert.Equal(t, "Yes", i.IIsClosedCaptioned)
+}
+
+func TestTranscriptAttachToText(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tr := newTranscript(t)
+	i := podcast.Item{}
+
+	// act
+	err := tr.AttachTo(&i, "http://example.com/1.html", podcast.TranscriptHTML)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, i.PTranscripts, 1)
+	assert.Len(t, i.PTranscripts[0].Rel, 0)
+	assert.Len(t, i.IIsClosedCaptioned, 0)
+}
+
+func TestTranscriptAttachToInvalid(t *testing.T) {
+	t.Parallel()
+
+	tr := newTranscript(t)
+	i := podcast.Item{}
+
+	assert.Error(t, tr.AttachTo(&i, "", podcast.TranscriptVTT))
+	assert.Error(t, tr.AttachTo(&i, "http://example.com/1.txt", "text/plain"))
+	assert.Len(t, i.PTranscripts, 0)
+}
@@ -1,19 +1,103 @@
 package podcast
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-/*
- * Ensures that the string passed in is compliant with RSS feed requirements specified in https://help.apple.com/itc/podcasts_connect/#/itc1723472cb
- */
+	"github.com/pkg/errors"
+)
+
+// GenerateFeedString ensures that str is compliant with the RSS feed
+// requirements specified in
+// https://help.apple.com/itc/podcasts_connect/#/itc1723472cb by stripping
+// the characters that are illegal in XML and normalising it to Unicode
+// NFC.
+//
+// The result is not escaped: Encode escapes all text exactly once, and
+// writes ©, ℗ and ™ as the character references Apple asks for.
 func GenerateFeedString(str string) string {
-	// TODO: Add necessary replacements for Gplay and Spotify too.
-	str = strings.Replace(str, "&", "&amp;", -1)
-	str = strings.Replace(str, "<", "&lt;", -1)
-	str = strings.Replace(str, ">", "&gt;", -1)
-	str = strings.Replace(str, "'", "&apos;", -1)
-	str = strings.Replace(str, "\"", "&quot;", -1)
-	str = strings.Replace(str, "©", "&#xA9;", -1)
-	str = strings.Replace(str, "℗", "&#x2117;", -1)
-	str = strings.Replace(str, "™", "&#x2122;", -1)
-	return str
+	return sanitizeText(str)
+}
+
+// truncateRunes shortens str to at most max runes.
+func truncateRunes(str string, max int) string {
+	if utf8.RuneCountInString(str) <= max {
+		return str
+	}
+	return string([]rune(str)[0:max])
+}
+
+// containsString reports whether str is one of strs.
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt reports whether n is one of ns.
+func containsInt(ns []int, n int) bool {
+	for _, each := range ns {
+		if each == n {
+			return true
+		}
+	}
+	return false
+}
+
+// rfc2822Zones are the zone names of RFC 2822, which time.Parse would
+// otherwise take as an unknown zone at UTC.
+var rfc2822Zones = map[string]string{
+	"UT":  "+0000",
+	"UTC": "+0000",
+	"GMT": "+0000",
+	"EST": "-0500",
+	"EDT": "-0400",
+	"CST": "-0600",
+	"CDT": "-0500",
+	"MST": "-0700",
+	"MDT": "-0600",
+	"PST": "-0800",
+	"PDT": "-0700",
+}
+
+// parsePubDate parses an RFC 2822 pubDate.  The weekday is optional but
+// has to match the date, the day may have one digit, the seconds are
+// optional and the zone is numeric or one of the RFC 2822 zone names.
+func parsePubDate(datetime string) (time.Time, error) {
+	fields := strings.Fields(datetime)
+	var weekday string
+	if len(fields) != 0 && strings.HasSuffix(fields[0], ",") {
+		weekday = strings.TrimSuffix(fields[0], ",")
+		fields = fields[1:]
+	}
+	if len(fields) != 0 {
+		if zone, ok := rfc2822Zones[strings.ToUpper(fields[len(fields)-1])]; ok {
+			fields[len(fields)-1] = zone
+		}
+	}
+	value := strings.Join(fields, " ")
+
+	t, err := time.Parse("2 Jan 2006 15:04:05 -0700", value)
+	if err != nil {
+		t, err = time.Parse("2 Jan 2006 15:04 -0700", value)
+	}
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is not an RFC 2822 date", datetime)
+	}
+	if len(weekday) != 0 && !strings.EqualFold(weekday, t.Weekday().String()[:3]) {
+		return time.Time{}, errors.Errorf("%q is not a %s", datetime, weekday)
+	}
+	return t, nil
+}
+
+// formatNormalPlayTime formats seconds as HH:MM:SS.mmm.
+func formatNormalPlayTime(seconds float64) string {
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
 }
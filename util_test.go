@@ -1,6 +1,8 @@
 package podcast_test
 
 import (
+	"bytes"
+	"io/ioutil"
 	"testing"
 
 	podcast "github.com/georgboe/rss-feed-generator"
@@ -20,7 +22,7 @@ func TestGenerateStringAmpersand(t *testing.T) {
 
 	result := podcast.GenerateFeedString("Kids & Family")
 
-	assert.Equal(t, result, "Kids &amp; Family")
+	assert.Equal(t, result, "Kids & Family")
 
 }
 
@@ -29,7 +31,7 @@ func TestGenerateStringLessThan(t *testing.T) {
 
 	result := podcast.GenerateFeedString("1 < 3")
 
-	assert.Equal(t, result, "1 &lt; 3")
+	assert.Equal(t, result, "1 < 3")
 }
 
 func TestGenerateStringGreaterThan(t *testing.T) {
@@ -37,7 +39,7 @@ func TestGenerateStringGreaterThan(t *testing.T) {
 
 	result := podcast.GenerateFeedString("3 > 1")
 
-	assert.Equal(t, result, "3 &gt; 1")
+	assert.Equal(t, result, "3 > 1")
 }
 
 func TestGenerateStringApostrophe(t *testing.T) {
@@ -45,7 +47,7 @@ func TestGenerateStringApostrophe(t *testing.T) {
 
 	result := podcast.GenerateFeedString("Sophie's choice")
 
-	assert.Equal(t, result, "Sophie&apos;s choice")
+	assert.Equal(t, result, "Sophie's choice")
 }
 
 func TestGenerateStringQuotation(t *testing.T) {
@@ -53,7 +55,7 @@ func TestGenerateStringQuotation(t *testing.T) {
 
 	result := podcast.GenerateFeedString("He said \"what\"")
 
-	assert.Equal(t, result, "He said &quot;what&quot;")
+	assert.Equal(t, result, "He said \"what\"")
 }
 
 func TestGenerateStringCopyrightSign(t *testing.T) {
@@ -61,7 +63,7 @@ func TestGenerateStringCopyrightSign(t *testing.T) {
 
 	result := podcast.GenerateFeedString("© Podpal Inc, 2020")
 
-	assert.Equal(t, result, "&#xA9; Podpal Inc, 2020")
+	assert.Equal(t, result, "© Podpal Inc, 2020")
 }
 
 func TestGenerateStringSoundRecordingCopyright(t *testing.T) {
@@ -69,7 +71,7 @@ func TestGenerateStringSoundRecordingCopyright(t *testing.T) {
 
 	result := podcast.GenerateFeedString("℗ Podpal Inc, 2020")
 
-	assert.Equal(t, result, "&#x2117; Podpal Inc, 2020")
+	assert.Equal(t, result, "℗ Podpal Inc, 2020")
 }
 
 func TestGenerateStringTrademark(t *testing.T) {
@@ -77,5 +79,74 @@ func TestGenerateStringTrademark(t *testing.T) {
 
 	result := podcast.GenerateFeedString("™ Podpal Inc, 2020")
 
-	assert.Equal(t, result, "&#x2122; Podpal Inc, 2020")
+	assert.Equal(t, result, "™ Podpal Inc, 2020")
+}
+
+func TestGenerateStringIllegalCharacters(t *testing.T) {
+	t.Parallel()
+
+	result := podcast.GenerateFeedString("Bell\x07 and\x00 null\uFFFE")
+
+	assert.Equal(t, "Bell and null", result)
+}
+
+func TestGenerateStringNormalizesUnicode(t *testing.T) {
+	t.Parallel()
+
+	result := podcast.GenerateFeedString("Cafe\u0301")
+
+	assert.Equal(t, "Caf\u00e9", result)
+}
+
+func TestEncodeEscapesOnce(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("Kids & Family <Weekly>", "http://example.com/", podcast.Description{Text: "<p>© in CDATA</p>"}, nil, nil)
+	p.AddCopyright("© Podpal Inc, 2020 ℗ 2021")
+	p.AddAuthor([]string{"Aaron's \"Woods\""})
+	p.AddCategory("Kids & Family", []string{"Pets & Animals"})
+	p.AddSubTitle("Podpal™\x0b")
+	i := podcast.Item{Title: "Q&A™", Link: "http://example.com/1?a=1&b=2"}
+	i.AddDescription(podcast.Description{Text: "Fish & Chips"})
+	if _, err := p.AddItem(i); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	var b bytes.Buffer
+	err := p.Encode(&b)
+
+	// assert
+	assert.NoError(t, err)
+	golden := "testdata/escaping.golden"
+	if *update {
+		if err := ioutil.WriteFile(golden, b.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, _ := ioutil.ReadFile(golden)
+	assert.Equal(t, string(expected), b.String())
+}
+
+func TestEncodeLeavesPodcastUnchanged(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "desc"}, nil, nil)
+	p.Title = "Café \x01"
+	i := &podcast.Item{Title: "Café\x01", Link: "http://example.com/1"}
+	p.Items = append(p.Items, i)
+
+	// act
+	out := p.String()
+	var b bytes.Buffer
+	err := p.EncodeStream(&b, podcast.ItemsFromSlice([]*podcast.Item{i}))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out, "<title>Café </title>")
+	assert.Contains(t, out, "<title>Café</title>")
+	assert.Equal(t, "Café \x01", p.Title)
+	assert.Equal(t, "Café\x01", i.Title)
 }
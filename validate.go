@@ -0,0 +1,642 @@
+package podcast
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/georgboe/rss-feed-generator/taxonomy"
+)
+
+// Severity ranks how serious a validation Finding is.
+type Severity int
+
+// Severity levels of a Finding.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the lower-case name of the Severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Finding is a single rule violation reported by Podcast.Validate.
+type Finding struct {
+	Severity Severity
+	// Field is the path of the offending field, such as "IOwner.Email"
+	// or "Items[2].Enclosure.URL".
+	Field string
+	// Rule is the ID of the ValidationRule that produced the Finding.
+	Rule    string
+	Message string
+}
+
+// String formats the Finding as "severity: rule: field: message".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s", f.Severity, f.Rule, f.Field, f.Message)
+}
+
+// Findings is the result of Podcast.Validate.
+type Findings []Finding
+
+// HasErrors reports whether any Finding is of SeverityError.
+func (fs Findings) HasErrors() bool {
+	for _, f := range fs {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationRule checks a single requirement of a directory.
+//
+// Check returns the paths of the fields that violate the rule, if any.
+type ValidationRule struct {
+	ID       string
+	Severity Severity
+	Message  string
+	Check    func(p *Podcast) []string
+}
+
+// ValidationProfile is a named set of ValidationRules, typically the
+// requirements of a single podcast directory.
+type ValidationProfile struct {
+	Name  string
+	Rules []ValidationRule
+}
+
+// AppleProfile validates against the Apple Podcasts requirements.
+//
+// https://podcasters.apple.com/support/823-podcast-requirements
+var AppleProfile = ValidationProfile{
+	Name: "Apple Podcasts",
+	Rules: []ValidationRule{
+		{"apple.title.required", SeverityError, "title is required", checkTitle},
+		{"apple.description.required", SeverityError, "description is required", checkDescription},
+		{"apple.language.required", SeverityError, "language is required", checkLanguage},
+		{"apple.artwork.required", SeverityError, "itunes:image is required", checkArtwork},
+		{"apple.artwork.format", SeverityError, "artwork must be a .jpg or .png file", checkArtworkFormat},
+		{"apple.category.required", SeverityError, "at least one itunes:category is required", checkCategoryRequired},
+		{"apple.category.valid", SeverityError, "category is not in the Apple Podcasts taxonomy", checkCategoryValid},
+		{"apple.explicit.required", SeverityError, "itunes:explicit must be yes/no or true/false", checkExplicit},
+		{"apple.author.required", SeverityWarning, "itunes:author is recommended", checkAuthor},
+		{"apple.owner.email", SeverityWarning, "itunes:owner email is needed to verify ownership", checkOwnerEmail},
+		{"apple.summary.length", SeverityError, "summary must not exceed 4000 characters", checkSummaryLength},
+		{"apple.type.valid", SeverityError, "itunes:type must be episodic or serial", checkShowType},
+		{"apple.item.title.required", SeverityError, "episode title is required", checkItemTitle},
+		{"apple.item.enclosure.required", SeverityError, "episode enclosure is required", checkItemEnclosure},
+		{"apple.item.enclosure.type", SeverityError, "enclosure type is not supported by Apple Podcasts",
+			checkItemEnclosureType("audio/x-m4a", "audio/mpeg", "video/quicktime", "video/mp4", "video/x-m4v", "application/pdf")},
+		{"apple.item.guid.required", SeverityError, "episode guid is required", checkItemGUID},
+		{"apple.item.guid.unique", SeverityError, "episode guid must be unique", checkItemGUIDUnique},
+		{"apple.item.pubdate.format", SeverityWarning, "pubDate must be in RFC 2822 format", checkItemPubDate},
+		{"apple.item.episodetype.valid", SeverityError, "itunes:episodeType must be full, trailer or bonus", checkItemEpisodeType},
+		{"apple.item.summary.length", SeverityError, "summary must not exceed 4000 characters", checkItemSummaryLength},
+	},
+}
+
+// SpotifyProfile validates against the Spotify podcast delivery
+// requirements.
+//
+// https://podcasters.spotify.com/terms/Spotify_Podcast_Delivery_Specification_v1.9.pdf
+var SpotifyProfile = ValidationProfile{
+	Name: "Spotify",
+	Rules: []ValidationRule{
+		{"spotify.title.required", SeverityError, "title is required", checkTitle},
+		{"spotify.description.required", SeverityError, "description is required", checkDescription},
+		{"spotify.language.required", SeverityError, "language is required", checkLanguage},
+		{"spotify.artwork.required", SeverityError, "itunes:image is required", checkArtwork},
+		{"spotify.category.required", SeverityWarning, "at least one itunes:category is recommended", checkCategoryRequired},
+		{"spotify.owner.email", SeverityError, "itunes:owner email is needed to claim the podcast", checkOwnerEmail},
+		{"spotify.items.required", SeverityError, "at least one episode is required", checkItemsRequired},
+		{"spotify.item.title.required", SeverityError, "episode title is required", checkItemTitle},
+		{"spotify.item.enclosure.required", SeverityError, "episode enclosure is required", checkItemEnclosure},
+		{"spotify.item.enclosure.type", SeverityError, "enclosure type is not supported by Spotify",
+			checkItemEnclosureType("audio/mpeg", "audio/x-m4a", "audio/mp4", "audio/aac", "video/mp4")},
+		{"spotify.item.guid.required", SeverityError, "episode guid is required", checkItemGUID},
+		{"spotify.item.guid.unique", SeverityError, "episode guid must be unique", checkItemGUIDUnique},
+		{"spotify.item.pubdate.required", SeverityError, "episode pubDate is required", checkItemPubDateRequired},
+		{"spotify.item.pubdate.format", SeverityError, "pubDate must be in RFC 2822 format", checkItemPubDate},
+	},
+}
+
+// PodcastIndexProfile validates the Podcasting 2.0 namespace elements.
+//
+// https://github.com/Podcastindex-org/podcast-namespace/blob/main/docs/1.0.md
+var PodcastIndexProfile = ValidationProfile{
+	Name: "Podcasting 2.0",
+	Rules: []ValidationRule{
+		{"podcastindex.guid.required", SeverityWarning, "podcast:guid is recommended", checkPodcastGUID},
+		{"podcastindex.guid.format", SeverityError, "podcast:guid must be a UUIDv5", checkPodcastGUIDFormat},
+		{"podcastindex.locked.required", SeverityWarning, "podcast:locked is recommended", checkLocked},
+		{"podcastindex.locked.value", SeverityError, "podcast:locked must be yes or no", checkLockedValue},
+		{"podcastindex.funding.url", SeverityError, "podcast:funding url is required", checkFundingURL},
+		{"podcastindex.person.name", SeverityError, "podcast:person name is required", checkPersonName},
+		{"podcastindex.item.transcript.valid", SeverityError, "podcast:transcript url and type are required", checkItemTranscripts},
+		{"podcastindex.item.chapters.type", SeverityWarning, "podcast:chapters type should be application/json+chapters", checkItemChaptersType},
+		{"podcastindex.item.chapters.bounds", SeverityError, "chapters must start within the episode duration", checkItemChaptersBounds},
+		{"podcastindex.item.soundbite.bounds", SeverityError, "podcast:soundbite must have a non-negative start and a positive duration", checkItemSoundbites},
+		{"podcastindex.item.alternateenclosure.valid", SeverityError, "podcast:alternateEnclosure type and a source uri are required", checkItemAlternateEnclosures},
+		{"podcastindex.value.valid", SeverityError, "podcast:value must have recipients with non-negative splits and marked fees", checkValue},
+	},
+}
+
+// RSSProfile validates the RSS 2.0 elements that carry structured values.
+//
+// https://www.rssboard.org/rss-specification
+var RSSProfile = ValidationProfile{
+	Name: "RSS 2.0",
+	Rules: []ValidationRule{
+		{"rss.cloud.valid", SeverityError, "cloud domain, path, port and a known protocol are required", checkCloud},
+		{"rss.skiphours.valid", SeverityError, "skipHours must be unique hours between 0 and 23", checkSkipHours},
+		{"rss.skipdays.valid", SeverityError, "skipDays must be unique days of the week", checkSkipDays},
+		{"rss.item.source.url", SeverityError, "source url is required", checkItemSource},
+	},
+}
+
+// SerialProfile checks the season and episode numbering of the Items,
+// see Podcast.OrderEpisodes.
+//
+// https://podcasters.apple.com/support/5533-episode-and-season-numbers
+var SerialProfile = ValidationProfile{
+	Name: "Serial show",
+	Rules: []ValidationRule{
+		{"serial.type.valid", SeverityError, "itunes:type must be episodic or serial", checkShowTypeRequired},
+		{"serial.number.valid", SeverityError, "season and episode numbers must be positive integers", checkEpisodeNumbers},
+		{"serial.episode.required", SeverityWarning, "full episodes of a serial show should be numbered", checkEpisodeRequired},
+		{"serial.episode.duplicate", SeverityError, "episode numbers must be unique within a season", checkEpisodeDuplicate},
+		{"serial.episode.gap", SeverityWarning, "episode numbers should not skip within a season", checkEpisodeGap},
+		{"serial.season.gap", SeverityWarning, "season numbers should not skip", checkSeasonGap},
+		{"serial.trailer.duplicate", SeverityWarning, "a season should have a single trailer", checkTrailerDuplicate},
+	},
+}
+
+// Validate runs every rule of the profile against the Podcast and returns
+// the Findings, in rule order.  An empty result means the Podcast passed.
+//
+// Validate never modifies the Podcast.
+func (p *Podcast) Validate(profile ValidationProfile) Findings {
+	var findings Findings
+	for _, rule := range profile.Rules {
+		for _, field := range rule.Check(p) {
+			findings = append(findings, Finding{
+				Severity: rule.Severity,
+				Field:    field,
+				Rule:     rule.ID,
+				Message:  rule.Message,
+			})
+		}
+	}
+	return findings
+}
+
+var (
+	artworkRE = regexp.MustCompile(`(?i)\.(jpe?g|png)(\?.*)?$`)
+	uuidV5RE  = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+)
+
+// failIf returns field when failed is true, for single-field rules.
+func failIf(failed bool, field string) []string {
+	if failed {
+		return []string{field}
+	}
+	return nil
+}
+
+// itemFields returns the field of the Items at the indexes, in the order
+// of the Items.
+func itemFields(indexes []int, field string) []string {
+	sort.Ints(indexes)
+	fields := make([]string, 0, len(indexes))
+	for _, n := range indexes {
+		fields = append(fields, fmt.Sprintf("Items[%d].%s", n, field))
+	}
+	return fields
+}
+
+// eachItem runs check against every Item and prefixes the returned field
+// with the Item path.
+func eachItem(p *Podcast, check func(i *Item) []string) []string {
+	var fields []string
+	for n, i := range p.Items {
+		for _, f := range check(i) {
+			fields = append(fields, fmt.Sprintf("Items[%d].%s", n, f))
+		}
+	}
+	return fields
+}
+
+func checkTitle(p *Podcast) []string {
+	return failIf(len(strings.TrimSpace(p.Title)) == 0, "Title")
+}
+
+func checkDescription(p *Podcast) []string {
+	return failIf(p.Description == nil || len(strings.TrimSpace(p.Description.Text)) == 0, "Description")
+}
+
+func checkLanguage(p *Podcast) []string {
+	return failIf(len(p.Language) < 2, "Language")
+}
+
+func checkArtwork(p *Podcast) []string {
+	return failIf(p.IImage == nil || len(p.IImage.HREF) == 0, "IImage")
+}
+
+func checkArtworkFormat(p *Podcast) []string {
+	return failIf(p.IImage != nil && len(p.IImage.HREF) != 0 && !artworkRE.MatchString(p.IImage.HREF), "IImage.HREF")
+}
+
+func checkCategoryRequired(p *Podcast) []string {
+	return failIf(len(p.ICategories) == 0, "ICategories")
+}
+
+func checkCategoryValid(p *Podcast) []string {
+	var fields []string
+	for n, c := range p.ICategories {
+		parent, ok := findTaxonomyCategory(c.Text)
+		if !ok || len(parent.Parent()) != 0 {
+			fields = append(fields, fmt.Sprintf("ICategories[%d].Text", n))
+			continue
+		}
+		for m, sub := range c.ICategories {
+			if child, ok := findTaxonomyCategory(sub.Text); !ok || child.Parent() != parent {
+				fields = append(fields, fmt.Sprintf("ICategories[%d].ICategories[%d].Text", n, m))
+			}
+		}
+	}
+	return fields
+}
+
+// findTaxonomyCategory looks up the category, which Apple only accepts
+// spelled exactly as in the taxonomy.
+func findTaxonomyCategory(name string) (taxonomy.Category, bool) {
+	name = html.UnescapeString(name)
+	c, err := taxonomy.Lookup(name)
+	return c, err == nil && c.String() == name
+}
+
+func checkExplicit(p *Podcast) []string {
+	switch strings.ToLower(p.IExplicit) {
+	case "yes", "no", "true", "false":
+		return nil
+	}
+	return []string{"IExplicit"}
+}
+
+func checkAuthor(p *Podcast) []string {
+	return failIf(len(p.IAuthor) == 0, "IAuthor")
+}
+
+func checkOwnerEmail(p *Podcast) []string {
+	return failIf(p.IOwner == nil || !strings.Contains(p.IOwner.Email, "@"), "IOwner.Email")
+}
+
+func checkSummaryLength(p *Podcast) []string {
+	return failIf(p.ISummary != nil && utf8.RuneCountInString(p.ISummary.Text) > 4000, "ISummary")
+}
+
+func checkShowType(p *Podcast) []string {
+	return failIf(p.IType != "" && !validShowType(p.IType), "IType")
+}
+
+func checkShowTypeRequired(p *Podcast) []string {
+	return failIf(!validShowType(p.IType), "IType")
+}
+
+func checkItemsRequired(p *Podcast) []string {
+	return failIf(len(p.Items) == 0, "Items")
+}
+
+func checkItemTitle(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		return failIf(len(strings.TrimSpace(i.Title)) == 0, "Title")
+	})
+}
+
+func checkItemEnclosure(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		return failIf(i.Enclosure == nil || len(i.Enclosure.URL) == 0, "Enclosure")
+	})
+}
+
+func checkItemEnclosureType(types ...string) func(p *Podcast) []string {
+	return func(p *Podcast) []string {
+		return eachItem(p, func(i *Item) []string {
+			if i.Enclosure == nil {
+				return nil
+			}
+			for _, t := range types {
+				if i.Enclosure.TypeFormatted == t {
+					return nil
+				}
+			}
+			return []string{"Enclosure.TypeFormatted"}
+		})
+	}
+}
+
+func checkItemGUID(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		return failIf(i.GUID == nil || len(i.GUID.Value) == 0, "GUID")
+	})
+}
+
+func checkItemGUIDUnique(p *Podcast) []string {
+	seen := make(map[string]bool)
+	return eachItem(p, func(i *Item) []string {
+		if i.GUID == nil || len(i.GUID.Value) == 0 {
+			return nil
+		}
+		duplicate := seen[i.GUID.Value]
+		seen[i.GUID.Value] = true
+		return failIf(duplicate, "GUID")
+	})
+}
+
+func checkItemPubDateRequired(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		return failIf(len(i.PubDate) == 0, "PubDate")
+	})
+}
+
+func checkItemPubDate(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		if len(i.PubDate) == 0 {
+			return nil
+		}
+		_, err := parsePubDate(i.PubDate)
+		return failIf(err != nil, "PubDate")
+	})
+}
+
+func checkItemEpisodeType(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		switch i.EpisodeType {
+		case "", EpisodeTypeFull, EpisodeTypeTrailer, EpisodeTypeBonus:
+			return nil
+		}
+		return []string{"EpisodeType"}
+	})
+}
+
+func checkItemSummaryLength(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		return failIf(i.ISummary != nil && utf8.RuneCountInString(i.ISummary.Text) > 4000, "ISummary")
+	})
+}
+
+func checkPodcastGUID(p *Podcast) []string {
+	return failIf(p.PGUID == nil, "PGUID")
+}
+
+func checkPodcastGUIDFormat(p *Podcast) []string {
+	return failIf(p.PGUID != nil && !uuidV5RE.MatchString(p.PGUID.Value), "PGUID.Value")
+}
+
+func checkLocked(p *Podcast) []string {
+	return failIf(p.PLocked == nil, "PLocked")
+}
+
+func checkLockedValue(p *Podcast) []string {
+	return failIf(p.PLocked != nil && p.PLocked.Value != "yes" && p.PLocked.Value != "no", "PLocked.Value")
+}
+
+func checkFundingURL(p *Podcast) []string {
+	var fields []string
+	for n, f := range p.PFunding {
+		if len(f.URL) == 0 {
+			fields = append(fields, fmt.Sprintf("PFunding[%d].URL", n))
+		}
+	}
+	return fields
+}
+
+func checkPersonName(p *Podcast) []string {
+	var fields []string
+	for n, person := range p.PPersons {
+		if len(person.Name) == 0 {
+			fields = append(fields, fmt.Sprintf("PPersons[%d].Name", n))
+		}
+	}
+	return append(fields, eachItem(p, func(i *Item) []string {
+		var fields []string
+		for n, person := range i.PPersons {
+			if len(person.Name) == 0 {
+				fields = append(fields, fmt.Sprintf("PPersons[%d].Name", n))
+			}
+		}
+		return fields
+	})...)
+}
+
+func checkItemTranscripts(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		var fields []string
+		for n, t := range i.PTranscripts {
+			if len(t.URL) == 0 || len(t.Type) == 0 {
+				fields = append(fields, fmt.Sprintf("PTranscripts[%d]", n))
+			}
+		}
+		return fields
+	})
+}
+
+func checkItemChaptersType(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		return failIf(i.PChapters != nil && i.PChapters.Type != "application/json+chapters", "PChapters.Type")
+	})
+}
+
+func checkItemChaptersBounds(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		var fields []string
+		for n, c := range i.Chapters {
+			if c.StartTime < 0 || i.chapterOutOfBounds(c) {
+				fields = append(fields, fmt.Sprintf("Chapters[%d]", n))
+			}
+		}
+		return fields
+	})
+}
+
+func checkItemSoundbites(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		var fields []string
+		for n, s := range i.PSoundbites {
+			if s.StartTime < 0 || s.Duration <= 0 {
+				fields = append(fields, fmt.Sprintf("PSoundbites[%d]", n))
+			}
+		}
+		return fields
+	})
+}
+
+func checkItemAlternateEnclosures(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		var fields []string
+		for n, ae := range i.PAlternateEnclosures {
+			valid := len(ae.Type) != 0 && len(ae.Sources) != 0
+			for _, s := range ae.Sources {
+				valid = valid && len(s.URI) != 0
+			}
+			if !valid {
+				fields = append(fields, fmt.Sprintf("PAlternateEnclosures[%d]", n))
+			}
+		}
+		return fields
+	})
+}
+
+func checkValue(p *Podcast) []string {
+	fields := failIf(p.PValue != nil && p.PValue.Validate() != nil, "PValue")
+	return append(fields, eachItem(p, func(i *Item) []string {
+		return failIf(i.PValue != nil && i.PValue.Validate() != nil, "PValue")
+	})...)
+}
+
+func checkCloud(p *Podcast) []string {
+	return failIf(p.Cloud != nil && !validCloud(p.Cloud), "Cloud")
+}
+
+func checkSkipHours(p *Podcast) []string {
+	if p.SkipHours == nil {
+		return nil
+	}
+	var fields []string
+	for n, h := range p.SkipHours.Hours {
+		if !validSkipHour(h) || containsInt(p.SkipHours.Hours[:n], h) {
+			fields = append(fields, fmt.Sprintf("SkipHours.Hours[%d]", n))
+		}
+	}
+	return fields
+}
+
+func checkSkipDays(p *Podcast) []string {
+	if p.SkipDays == nil {
+		return nil
+	}
+	var fields []string
+	for n, d := range p.SkipDays.Days {
+		if day, ok := skipDay(d); !ok || day != d || containsString(p.SkipDays.Days[:n], d) {
+			fields = append(fields, fmt.Sprintf("SkipDays.Days[%d]", n))
+		}
+	}
+	return fields
+}
+
+func checkItemSource(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		return failIf(i.Source != nil && len(i.Source.URL) == 0, "Source")
+	})
+}
+
+func checkEpisodeNumbers(p *Podcast) []string {
+	return eachItem(p, func(i *Item) []string {
+		var fields []string
+		if _, ok := episodeNumber(i.SeasonNumber); !ok {
+			fields = append(fields, "SeasonNumber")
+		}
+		if _, ok := episodeNumber(i.EpisodeNumber); !ok {
+			fields = append(fields, "EpisodeNumber")
+		}
+		return fields
+	})
+}
+
+func checkEpisodeRequired(p *Podcast) []string {
+	if p.IType != ShowTypeSerial {
+		return nil
+	}
+	return eachItem(p, func(i *Item) []string {
+		full := i.EpisodeType == "" || i.EpisodeType == EpisodeTypeFull
+		return failIf(full && len(strings.TrimSpace(i.EpisodeNumber)) == 0, "EpisodeNumber")
+	})
+}
+
+func checkEpisodeDuplicate(p *Podcast) []string {
+	var duplicates []int
+	for _, items := range fullEpisodes(p) {
+		seen := make(map[int]bool)
+		for _, n := range items {
+			episode := newEpisodeKey(p.Items[n]).episode
+			if seen[episode] {
+				duplicates = append(duplicates, n)
+			}
+			seen[episode] = true
+		}
+	}
+	return itemFields(duplicates, "EpisodeNumber")
+}
+
+func checkEpisodeGap(p *Podcast) []string {
+	var gaps []int
+	for _, items := range fullEpisodes(p) {
+		byEpisode := make(map[int]int)
+		var episodes []int
+		for _, n := range items {
+			episode := newEpisodeKey(p.Items[n]).episode
+			if _, seen := byEpisode[episode]; !seen {
+				byEpisode[episode] = n
+				episodes = append(episodes, episode)
+			}
+		}
+		sort.Ints(episodes)
+		for k := 1; k < len(episodes); k++ {
+			if episodes[k] != episodes[k-1]+1 {
+				gaps = append(gaps, byEpisode[episodes[k]])
+			}
+		}
+	}
+	return itemFields(gaps, "EpisodeNumber")
+}
+
+func checkSeasonGap(p *Podcast) []string {
+	bySeason := make(map[int]int)
+	var seasons []int
+	for n, i := range p.Items {
+		season, ok := episodeNumber(i.SeasonNumber)
+		if !ok || season == 0 {
+			continue
+		}
+		if _, seen := bySeason[season]; !seen {
+			bySeason[season] = n
+			seasons = append(seasons, season)
+		}
+	}
+	sort.Ints(seasons)
+	var gaps []int
+	for k := 1; k < len(seasons); k++ {
+		if seasons[k] != seasons[k-1]+1 {
+			gaps = append(gaps, bySeason[seasons[k]])
+		}
+	}
+	return itemFields(gaps, "SeasonNumber")
+}
+
+func checkTrailerDuplicate(p *Podcast) []string {
+	trailers := make(map[int]bool)
+	return eachItem(p, func(i *Item) []string {
+		if i.EpisodeType != EpisodeTypeTrailer {
+			return nil
+		}
+		season := newEpisodeKey(i).season
+		duplicate := trailers[season]
+		trailers[season] = true
+		return failIf(duplicate, "EpisodeType")
+	})
+}
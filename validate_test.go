@@ -0,0 +1,119 @@
+package podcast_test
+
+import (
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func newValidPodcast(t *testing.T) podcast.Podcast {
+	t.Helper()
+	p := podcast.New("title", "http://example.com/", podcast.Description{Text: "Description"}, nil, nil)
+	p.AddLanguage("en-us")
+	p.AddImage("http://example.com/i.jpg")
+	p.AddCategory("Arts", []string{"Books"})
+	p.AddParentalAdvisory(podcast.ParentalAdvisoryClean)
+	p.AddAuthor([]string{"Jane Doe"})
+	p.AddOwner("Jane Doe", "jane@example.com")
+
+	i := podcast.Item{Title: "Episode 1", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 1)
+	i.AddPubDate("Sun, 14 Mar 2021 18:34:05 +0000")
+	_, err := p.AddItem(i)
+	assert.NoError(t, err)
+	return p
+}
+
+func findingRules(fs podcast.Findings) []string {
+	rules := []string{}
+	for _, f := range fs {
+		rules = append(rules, f.Rule)
+	}
+	return rules
+}
+
+func TestValidateAppleValid(t *testing.T) {
+	t.Parallel()
+
+	p := newValidPodcast(t)
+
+	findings := p.Validate(podcast.AppleProfile)
+
+	assert.Empty(t, findings)
+	assert.False(t, findings.HasErrors())
+}
+
+func TestValidateAppleInvalid(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newValidPodcast(t)
+	p.Language = ""
+	p.AddImage("http://example.com/i.gif")
+	p.AddCategory("Kids & Family", []string{"Parenting", "Knitting"})
+	p.AddCategory("Cats", nil)
+	p.Items[0].GUID.Value = ""
+
+	// act
+	findings := p.Validate(podcast.AppleProfile)
+
+	// assert
+	assert.True(t, findings.HasErrors())
+	assert.Equal(t, []string{
+		"apple.language.required",
+		"apple.artwork.format",
+		"apple.category.valid",
+		"apple.category.valid",
+		"apple.item.guid.required",
+	}, findingRules(findings))
+	assert.Equal(t, "ICategories[1].ICategories[1].Text", findings[2].Field)
+	assert.Equal(t, "ICategories[2].Text", findings[3].Field)
+	assert.Equal(t, "Items[0].GUID", findings[4].Field)
+}
+
+func TestValidateItemGUIDUnique(t *testing.T) {
+	t.Parallel()
+
+	p := newValidPodcast(t)
+	i := podcast.Item{Title: "Episode 2", Description: &podcast.Description{Text: "desc"}}
+	i.AddEnclosure("http://example.com/1.mp3", podcast.MP3, "audio/mpeg", 1)
+	i.PubDate = "not a date"
+	_, _ = p.AddItem(i)
+
+	findings := p.Validate(podcast.AppleProfile)
+
+	assert.Equal(t, []string{"apple.item.guid.unique", "apple.item.pubdate.format"}, findingRules(findings))
+	assert.Equal(t, podcast.SeverityWarning, findings[1].Severity)
+	assert.Equal(t, "warning: apple.item.pubdate.format: Items[1].PubDate: pubDate must be in RFC 2822 format", findings[1].String())
+}
+
+func TestValidateSpotify(t *testing.T) {
+	t.Parallel()
+
+	p := newValidPodcast(t)
+	p.IOwner = nil
+	p.Items[0].PubDate = ""
+
+	findings := p.Validate(podcast.SpotifyProfile)
+
+	assert.Equal(t, []string{"spotify.owner.email", "spotify.item.pubdate.required"}, findingRules(findings))
+}
+
+func TestValidatePodcastIndex(t *testing.T) {
+	t.Parallel()
+
+	p := newValidPodcast(t)
+	p.AddPodcastGUID("not-a-guid")
+	p.AddFunding("https://example.com/donate", "")
+	p.Items[0].AddChapters("http://example.com/1.json", "application/json")
+
+	findings := p.Validate(podcast.PodcastIndexProfile)
+
+	assert.Equal(t, []string{
+		"podcastindex.guid.format",
+		"podcastindex.locked.required",
+		"podcastindex.item.chapters.type",
+	}, findingRules(findings))
+	assert.False(t, findings[1:].HasErrors())
+}
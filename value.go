@@ -0,0 +1,124 @@
+package podcast
+
+import (
+	"encoding/xml"
+
+	"github.com/pkg/errors"
+)
+
+// Specifications: https://github.com/Podcastindex-org/podcast-namespace/blob/main/value/value.md
+//
+
+// Value block types and methods supported by podcast apps.
+const (
+	ValueTypeLightning = "lightning"
+	ValueMethodKeysend = "keysend"
+	ValueRecipientNode = "node"
+)
+
+// PodcastValue describes how listeners can send payments, such as
+// Lightning streaming payments, to the show.
+//
+// At channel level it applies to every episode.  An Item with its own
+// PodcastValue overrides the channel split for that episode, e.g. to add
+// a guest; use Clone to start from the channel split.
+type PodcastValue struct {
+	XMLName    xml.Name `xml:"podcast:value"`
+	Type       string   `xml:"type,attr"`
+	Method     string   `xml:"method,attr"`
+	Suggested  string   `xml:"suggested,attr,omitempty"`
+	Recipients []*PodcastValueRecipient
+}
+
+// PodcastValueRecipient is a single destination of the payments.
+//
+// Split is the share of the payment sent to the recipient, relative to
+// the other recipients.  Fee recipients instead take Split as a
+// percentage off the top of the payment before the shares are paid.
+type PodcastValueRecipient struct {
+	XMLName     xml.Name `xml:"podcast:valueRecipient"`
+	Name        string   `xml:"name,attr,omitempty"`
+	CustomKey   string   `xml:"customKey,attr,omitempty"`
+	CustomValue string   `xml:"customValue,attr,omitempty"`
+	Type        string   `xml:"type,attr"`
+	Address     string   `xml:"address,attr"`
+	Split       int      `xml:"split,attr"`
+	Fee         bool     `xml:"fee,attr,omitempty"`
+}
+
+// NewValue instantiates a PodcastValue for Lightning keysend payments,
+// the only combination currently supported by podcast apps.
+func NewValue(suggested string) PodcastValue {
+	return PodcastValue{
+		Type:      ValueTypeLightning,
+		Method:    ValueMethodKeysend,
+		Suggested: suggested,
+	}
+}
+
+// AddRecipient adds a recipient that receives split shares of the
+// payments.  The recipient is not marked as a fee.
+func (v *PodcastValue) AddRecipient(recipient PodcastValueRecipient) {
+	recipient.Fee = false
+	v.addRecipient(recipient)
+}
+
+// AddFee adds a recipient, such as an app or hosting provider, that
+// takes split percent off the top of the payments.  The recipient is
+// marked as a fee.
+func (v *PodcastValue) AddFee(recipient PodcastValueRecipient) {
+	recipient.Fee = true
+	v.addRecipient(recipient)
+}
+
+func (v *PodcastValue) addRecipient(recipient PodcastValueRecipient) {
+	if len(recipient.Type) == 0 {
+		recipient.Type = ValueRecipientNode
+	}
+	v.Recipients = append(v.Recipients, &recipient)
+}
+
+// Clone returns a copy of the PodcastValue that can be changed without
+// changing the original, such as an Item override of the channel split.
+func (v *PodcastValue) Clone() PodcastValue {
+	c := *v
+	c.Recipients = make([]*PodcastValueRecipient, 0, len(v.Recipients))
+	for _, r := range v.Recipients {
+		rc := *r
+		c.Recipients = append(c.Recipients, &rc)
+	}
+	return c
+}
+
+// Validate returns an error describing the first problem with the
+// PodcastValue, or nil when it is valid.
+//
+// A valid value block has a type and method, and at least one recipient.
+// Every recipient has a type, an address and a non-negative split.  Fee
+// recipients take at most 100 percent, and the other recipients share a
+// split greater than zero.
+func (v *PodcastValue) Validate() error {
+	if len(v.Type) == 0 || len(v.Method) == 0 {
+		return errors.New("podcast:value type and method are required")
+	}
+	if len(v.Recipients) == 0 {
+		return errors.New("podcast:value requires at least one valueRecipient")
+	}
+	shares := 0
+	for n, r := range v.Recipients {
+		switch {
+		case len(r.Type) == 0 || len(r.Address) == 0:
+			return errors.Errorf("podcast:valueRecipient %d: type and address are required", n)
+		case r.Split < 0:
+			return errors.Errorf("podcast:valueRecipient %d: split %d is negative", n, r.Split)
+		case r.Fee && r.Split > 100:
+			return errors.Errorf("podcast:valueRecipient %d: fee split %d is more than 100 percent", n, r.Split)
+		case !r.Fee:
+			shares += r.Split
+		}
+	}
+	if shares == 0 {
+		return errors.New("podcast:value requires a non-fee valueRecipient with a split")
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package podcast_test
+
+import (
+	"testing"
+
+	podcast "github.com/georgboe/rss-feed-generator"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	hostNode  = "02d5c1bf8b940dc9cadca86d1b0a3c37fbe39cee4c7e839e33bef9174531d27f52"
+	appNode   = "03ae9f91a0cb8ff43840e3c322c4c61f019d8c1c3cea15a25cfc425ac605e61a4a"
+	guestNode = "032f4ffbbafffbe51726ad3c164a3d0d37ec27bc67b29a159b0f49ae8ac21b8508"
+)
+
+func newValue(t *testing.T) podcast.PodcastValue {
+	t.Helper()
+	v := podcast.NewValue("0.00000005000")
+	v.AddRecipient(podcast.PodcastValueRecipient{Name: "Host", Address: hostNode, Split: 95})
+	v.AddFee(podcast.PodcastValueRecipient{Name: "App", Address: appNode, Split: 5})
+	return v
+}
+
+func TestAddValue(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+
+	// act
+	err := p.AddValue(newValue(t))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, podcast.ValueTypeLightning, p.PValue.Type)
+	assert.Equal(t, podcast.ValueMethodKeysend, p.PValue.Method)
+	assert.Len(t, p.PValue.Recipients, 2)
+	assert.Equal(t, podcast.ValueRecipientNode, p.PValue.Recipients[0].Type)
+	assert.False(t, p.PValue.Recipients[0].Fee)
+	assert.True(t, p.PValue.Recipients[1].Fee)
+	assert.Contains(t, p.String(), `<podcast:valueRecipient name="App" type="node" address="`+appNode+`" split="5" fee="true"></podcast:valueRecipient>`)
+}
+
+func TestAddValueCustomKey(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	v := podcast.NewValue("")
+	v.AddRecipient(podcast.PodcastValueRecipient{
+		Name:        "Wallet",
+		Address:     hostNode,
+		CustomKey:   "696969",
+		CustomValue: "eChoVKtO1KujpAA5HCoB",
+		Split:       1,
+	})
+
+	// act
+	err := p.AddValue(v)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, p.String(), `customKey="696969" customValue="eChoVKtO1KujpAA5HCoB"`)
+}
+
+func TestAddValueInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value func(t *testing.T) podcast.PodcastValue
+	}{
+		{"no recipients", func(t *testing.T) podcast.PodcastValue {
+			return podcast.NewValue("")
+		}},
+		{"no type", func(t *testing.T) podcast.PodcastValue {
+			v := newValue(t)
+			v.Type = ""
+			return v
+		}},
+		{"no address", func(t *testing.T) podcast.PodcastValue {
+			v := newValue(t)
+			v.AddRecipient(podcast.PodcastValueRecipient{Name: "Guest", Split: 10})
+			return v
+		}},
+		{"negative split", func(t *testing.T) podcast.PodcastValue {
+			v := newValue(t)
+			v.AddRecipient(podcast.PodcastValueRecipient{Address: guestNode, Split: -10})
+			return v
+		}},
+		{"fee over 100", func(t *testing.T) podcast.PodcastValue {
+			v := podcast.NewValue("")
+			v.AddRecipient(podcast.PodcastValueRecipient{Address: hostNode, Split: 1})
+			v.AddFee(podcast.PodcastValueRecipient{Address: appNode, Split: 101})
+			return v
+		}},
+		{"only fees", func(t *testing.T) podcast.PodcastValue {
+			v := podcast.NewValue("")
+			v.AddFee(podcast.PodcastValueRecipient{Address: appNode, Split: 5})
+			return v
+		}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := podcast.Podcast{}
+
+			err := p.AddValue(tt.value(t))
+
+			assert.Error(t, err)
+			assert.Nil(t, p.PValue)
+		})
+	}
+}
+
+func TestItemAddValueOverride(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := podcast.New("title", "link", podcast.Description{Text: "Description"}, nil, nil)
+	assert.NoError(t, p.AddValue(newValue(t)))
+	v := p.PValue.Clone()
+	v.Recipients[0].Split = 45
+	v.AddRecipient(podcast.PodcastValueRecipient{Name: "Guest", Address: guestNode, Split: 50})
+	i := podcast.Item{}
+
+	// act
+	err := i.AddValue(v)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, i.PValue.Recipients, 3)
+	assert.Equal(t, 45, i.PValue.Recipients[0].Split)
+	assert.Len(t, p.PValue.Recipients, 2)
+	assert.Equal(t, 95, p.PValue.Recipients[0].Split)
+}
+
+func TestValidatePodcastValue(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	p := newValidPodcast(t)
+	v := newValue(t)
+	v.Recipients[0].Split = -1
+	p.Items[0].PValue = &v
+
+	// act
+	findings := p.Validate(podcast.PodcastIndexProfile)
+
+	// assert
+	assert.Contains(t, findings, podcast.Finding{
+		Severity: podcast.SeverityError,
+		Field:    "Items[0].PValue",
+		Rule:     "podcastindex.value.valid",
+		Message:  "podcast:value must have recipients with non-negative splits and marked fees",
+	})
+}